@@ -0,0 +1,145 @@
+package assert
+
+import (
+	"strings"
+
+	"github.com/ohmymajo/http-assert/pkg/filter"
+	"github.com/ohmymajo/http-assert/pkg/validation"
+)
+
+func (h HttpJson) WhereField(fieldName, op, otherField string) HttpJson {
+	var correct bool
+	if h.Type == "body" && h.evaluate() {
+		if err := h.bodyError(false); err != nil {
+			return h.bodyFailure(err, "WhereField(%q, %q, %q)", fieldName, op, otherField)
+		}
+
+		a := findValue(fieldName, h.Body)
+		b := findValue(otherField, h.Body)
+		if a != nil && b != nil {
+			correct = validation.CompareValues(a, b, op)
+		}
+	}
+
+	return h.result(correct, "WhereField(%q, %q, %q)", fieldName, op, otherField)
+}
+
+func (h HttpJson) WhereAggregate(fn, fieldName, op string, value interface{}) HttpJson {
+	if !aggregates[fn] {
+		return h.result(false, "WhereAggregate(%q, %q, %q, %#v): unknown aggregate function", fn, fieldName, op, value)
+	}
+
+	var correct bool
+	if h.Type == "body" && h.evaluate() {
+		if err := h.bodyError(false); err != nil {
+			return h.bodyFailure(err, "WhereAggregate(%q, %q, %q, %#v)", fn, fieldName, op, value)
+		}
+
+		v := aggregate(fn, findValues(fieldName, h.Body))
+		if v != nil {
+			correct = validation.CompareValues(v, value, op)
+		}
+	}
+
+	return h.result(correct, "WhereAggregate(%q, %q, %q, %#v)", fn, fieldName, op, value)
+}
+
+func (h HttpJson) WhereAggregateField(fn, fieldName, op, otherField string) HttpJson {
+	if !aggregates[fn] {
+		return h.result(false, "WhereAggregateField(%q, %q, %q, %q): unknown aggregate function", fn, fieldName, op, otherField)
+	}
+
+	var correct bool
+	if h.Type == "body" && h.evaluate() {
+		if err := h.bodyError(false); err != nil {
+			return h.bodyFailure(err, "WhereAggregateField(%q, %q, %q, %q)", fn, fieldName, op, otherField)
+		}
+
+		a := aggregate(fn, findValues(fieldName, h.Body))
+		b := findValue(otherField, h.Body)
+		if a != nil && b != nil {
+			correct = validation.CompareValues(a, b, op)
+		}
+	}
+
+	return h.result(correct, "WhereAggregateField(%q, %q, %q, %q)", fn, fieldName, op, otherField)
+}
+
+var aggregates = map[string]bool{
+	"count": true,
+	"sum":   true,
+	"avg":   true,
+	"min":   true,
+	"max":   true,
+}
+
+func findValue(fieldName string, body interface{}) interface{} {
+	values := filter.FindAll(fieldName, body)
+	if len(values) != 1 {
+		return nil
+	}
+
+	return values[0]
+}
+
+func findValues(fieldName string, body interface{}) []interface{} {
+	values := filter.FindAll(fieldName, body)
+	if len(values) == 1 && !strings.Contains(fieldName, "*") {
+		if arr, ok := values[0].([]interface{}); ok {
+			return arr
+		}
+	}
+
+	return values
+}
+
+func aggregate(fn string, values []interface{}) interface{} {
+	if fn == "count" {
+		return len(values)
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	var sumInt int
+	var sumFloat float64
+	var isFloat bool
+	var min, max interface{}
+	for _, v := range values {
+		num, t := validation.NumericValue(v)
+		if t == "" {
+			return nil
+		}
+
+		if t == "float" {
+			isFloat = true
+			sumFloat += num.(float64)
+		} else {
+			sumInt += num.(int)
+		}
+
+		if min == nil || validation.CompareValues(num, min, "lt") {
+			min = num
+		}
+		if max == nil || validation.CompareValues(num, max, "gt") {
+			max = num
+		}
+	}
+
+	switch fn {
+	case "sum":
+		if isFloat {
+			return sumFloat + float64(sumInt)
+		}
+		return sumInt
+	case "avg":
+		return (sumFloat + float64(sumInt)) / float64(len(values))
+	case "min":
+		return min
+	case "max":
+		return max
+	}
+
+	return nil
+}
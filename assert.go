@@ -1,11 +1,16 @@
 package assert
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/ohmymajo/http-assert/pkg/filter"
+	"github.com/ohmymajo/http-assert/pkg/header"
 	"github.com/ohmymajo/http-assert/pkg/validation"
 )
 
@@ -18,6 +23,10 @@ type HttpJson struct {
 	Header        *http.Header
 	Body          interface{}
 	AssertCorrect bool
+	Failures      []string
+	Resp          *http.Response
+	SoftMode      bool
+	Query         url.Values
 }
 
 func New(resp *http.Response) Http {
@@ -35,67 +44,79 @@ func (h Http) AssertHeader() HttpJson {
 		Type:          "header",
 		Header:        &h.Resp.Header,
 		AssertCorrect: true,
+		Resp:          h.Resp,
 	}
 }
 
 func (h Http) AssertBody() HttpJson {
-	var body interface{}
-
-	d := json.NewDecoder(h.Resp.Body)
-	d.UseNumber()
-	err := d.Decode(&body)
+	body, err := decodeBody(h.Resp)
 	if err != nil {
-		panic("cannot decode json data")
+		panic(err.Error())
 	}
 
 	return HttpJson{
 		Type:          "body",
+		Header:        &h.Resp.Header,
 		Body:          body,
 		AssertCorrect: true,
+		Resp:          h.Resp,
 	}
 }
 
+func decodeBody(resp *http.Response) (interface{}, error) {
+	var body interface{}
+
+	ct := resp.Header.Get("Content-Type")
+	if ct != "" && !header.IsJSONMediaType(ct) {
+		return nil, errors.New("cannot decode " + ct + " body as json data")
+	}
+
+	d := json.NewDecoder(bytes.NewReader(readBody(resp)))
+	d.UseNumber()
+	err := d.Decode(&body)
+	if err != nil {
+		return nil, errors.New("cannot decode json data")
+	}
+
+	return body, nil
+}
+
 func (h HttpJson) Has(fieldName string) HttpJson {
 	var correct bool
-	if h.Type == "header" && h.AssertCorrect {
+	if h.Type == "header" && h.evaluate() {
 		correct = h.Header.Get(fieldName) != ""
-	} else if h.Type == "body" && h.AssertCorrect {
-		f := strings.Split(fieldName, ".")
-		t := validation.GetBodyType(h.Body)
+	} else if h.Type == "cookie" && h.evaluate() {
+		correct = findCookie(h.Header, fieldName) != nil
+	} else if h.Type == "query" && h.evaluate() {
+		_, correct = queryValue(h.Query, fieldName)
+	} else if h.Type == "body" && h.evaluate() {
+		if err := h.bodyError(true); err != nil {
+			return h.bodyFailure(err, "Has(%q)", fieldName)
+		}
 
-		if t == "" {
-			panic("cannot read the response body")
-		} else if t == "object" {
-			if len(f) == 1 {
-				b := h.Body.(map[string]interface{})
-				for key := range b {
-					if key == fieldName {
-						correct = true
-						break
-					}
-				}
-			} else {
-				v := filter.Find(fieldName, h.Body)
-				if v != nil {
+		f := strings.Split(fieldName, ".")
+		if len(f) == 1 {
+			b := h.Body.(map[string]interface{})
+			for key := range b {
+				if key == fieldName {
 					correct = true
+					break
 				}
 			}
 		} else {
-			panic("body should be JSON object")
+			v := filter.Find(fieldName, h.Body)
+			if v != nil {
+				correct = true
+			}
 		}
 	}
 
-	return HttpJson{
-		Type:          h.Type,
-		Header:        h.Header,
-		Body:          h.Body,
-		AssertCorrect: correct,
-	}
+	return h.result(correct, "Has(%q)", fieldName)
 }
 
 func (h HttpJson) HasAll(fieldNames []string) HttpJson {
 	var correct bool
-	if h.Type == "header" && h.AssertCorrect {
+	if h.Type == "header" && h.evaluate() {
 		for _, fieldName := range fieldNames {
 			if h.Header.Get(fieldName) == "" {
 				correct = false
@@ -104,359 +125,267 @@ func (h HttpJson) HasAll(fieldNames []string) HttpJson {
 
 			correct = h.Header.Get(fieldName) != ""
 		}
-	} else if h.Type == "body" && h.AssertCorrect {
+	} else if h.Type == "cookie" && h.evaluate() {
+		for _, fieldName := range fieldNames {
+			correct = findCookie(h.Header, fieldName) != nil
+			if !correct {
+				break
+			}
+		}
+	} else if h.Type == "query" && h.evaluate() {
+		for _, fieldName := range fieldNames {
+			_, correct = queryValue(h.Query, fieldName)
+			if !correct {
+				break
+			}
+		}
+	} else if h.Type == "body" && h.evaluate() {
+		if err := h.bodyError(true); err != nil {
+			return h.bodyFailure(err, "HasAll(%q)", fieldNames)
+		}
+
 		for _, fieldName := range fieldNames {
 			f := strings.Split(fieldName, ".")
-			t := validation.GetBodyType(h.Body)
-
-			if t == "" {
-				panic("cannot read the response body")
-			} else if t == "object" {
-				if len(f) == 1 {
-					b := h.Body.(map[string]interface{})
-					for key := range b {
-						if key != fieldName {
-							correct = false
-							break
-						}
-						correct = key == fieldName
-					}
-				} else {
-					v := filter.Find(fieldName, h.Body)
-					if v == nil {
+			if len(f) == 1 {
+				b := h.Body.(map[string]interface{})
+				for key := range b {
+					if key != fieldName {
 						correct = false
 						break
-					} else {
-						correct = true
 					}
+					correct = key == fieldName
 				}
 			} else {
-				panic("body should be JSON object")
+				v := filter.Find(fieldName, h.Body)
+				if v == nil {
+					correct = false
+					break
+				} else {
+					correct = true
+				}
 			}
 		}
 	}
 
-	return HttpJson{
-		Type:          h.Type,
-		Header:        h.Header,
-		Body:          h.Body,
-		AssertCorrect: correct,
-	}
+	return h.result(correct, "HasAll(%q)", fieldNames)
 }
 
 func (h HttpJson) HasLength(fieldName string, length int) HttpJson {
 	var correct bool
-	if h.Type == "body" && h.AssertCorrect {
-		f := strings.Split(fieldName, ".")
-		t := validation.GetBodyType(h.Body)
-
-		if t == "" {
-			panic("cannot read the response body")
-		} else if t == "object" {
-			if len(f) == 1 {
-				b := h.Body.(map[string]interface{})
-				for key, val := range b {
-					if key == fieldName {
-						correct = len(val.([]interface{})) == length
-						break
-					}
-				}
-			} else {
-				v := filter.Find(fieldName, h.Body)
-				if v != nil {
-					correct = len(v.([]interface{})) == length
-				}
-			}
-		} else {
-			panic("body should be JSON object")
+	if (h.Type == "header" || h.Type == "query" || h.Type == "body") && h.evaluate() {
+		l, ok, err := h.lengthOf(fieldName)
+		if err != nil {
+			return h.bodyFailure(err, "HasLength(%q, %d)", fieldName, length)
 		}
+		correct = ok && l == length
 	}
 
-	return HttpJson{
-		Type:          h.Type,
-		Header:        h.Header,
-		Body:          h.Body,
-		AssertCorrect: correct,
-	}
+	return h.result(correct, "HasLength(%q, %d)", fieldName, length)
 }
 
 func (h HttpJson) Where(fieldName string, value interface{}) HttpJson {
 	var correct bool
-	if h.Type == "header" && h.AssertCorrect {
+	if h.Type == "header" && h.evaluate() {
 		hVal := h.Header.Get(fieldName)
 		correct = hVal == value.(string)
-	} else if h.Type == "body" && h.AssertCorrect {
-		f := strings.Split(fieldName, ".")
-		t := validation.GetBodyType(h.Body)
-
-		if t == "" {
-			panic("cannot read the response body")
-		} else if t == "object" {
-			if len(f) == 1 {
-				b := h.Body.(map[string]interface{})
-				for key, val := range b {
-					if key == fieldName {
-						vType := validation.GetValueType(value)
-						correct = validation.EqualValue(value, val, vType)
-
-						break
-					}
-				}
-			} else {
-				v := filter.Find(fieldName, h.Body)
-
-				t = validation.GetValueType(value)
-				correct = validation.EqualValue(v, value, t)
-			}
-		} else {
-			panic("body should be JSON object")
+	} else if h.Type == "cookie" && h.evaluate() {
+		c := findCookie(h.Header, fieldName)
+		correct = c != nil && c.Value == value.(string)
+	} else if h.Type == "query" && h.evaluate() {
+		v, ok := queryValue(h.Query, fieldName)
+		correct = ok && v == value.(string)
+	} else if h.Type == "body" && h.evaluate() {
+		v, ok, err := h.bodyValue(fieldName)
+		if err != nil {
+			return h.bodyFailure(err, "Where(%q, %#v)", fieldName, value)
 		}
+		correct = ok && equalValue(v, value)
 	}
 
-	return HttpJson{
-		Type:          h.Type,
-		Header:        h.Header,
-		Body:          h.Body,
-		AssertCorrect: correct,
-	}
+	return h.result(correct, "Where(%q, %#v)", fieldName, value)
 }
 
 func (h HttpJson) WhereNot(fieldName string, value interface{}) HttpJson {
 	var correct bool
-	if h.Type == "header" && h.AssertCorrect {
+	if h.Type == "header" && h.evaluate() {
 		hVal := h.Header.Get(fieldName)
-		correct = hVal == value.(string)
-	} else if h.Type == "body" && h.AssertCorrect {
-		f := strings.Split(fieldName, ".")
-		t := validation.GetBodyType(h.Body)
-
-		if t == "" {
-			panic("cannot read the response body")
-		} else if t == "object" {
-			if len(f) == 1 {
-				b := h.Body.(map[string]interface{})
-				for key, val := range b {
-					if key == fieldName {
-						vType := validation.GetValueType(value)
-						correct = validation.NotEqualValue(value, val, vType)
-
-						break
-					}
-				}
-			} else {
-				v := filter.Find(fieldName, h.Body)
-
-				t = validation.GetValueType(value)
-				correct = validation.NotEqualValue(v, value, t)
-			}
-		} else {
-			panic("body should be JSON object")
+		correct = hVal != value.(string)
+	} else if h.Type == "cookie" && h.evaluate() {
+		c := findCookie(h.Header, fieldName)
+		correct = c != nil && c.Value != value.(string)
+	} else if h.Type == "query" && h.evaluate() {
+		v, ok := queryValue(h.Query, fieldName)
+		correct = ok && v != value.(string)
+	} else if h.Type == "body" && h.evaluate() {
+		v, ok, err := h.bodyValue(fieldName)
+		if err != nil {
+			return h.bodyFailure(err, "WhereNot(%q, %#v)", fieldName, value)
 		}
+		correct = ok && !equalValue(v, value)
 	}
 
-	return HttpJson{
-		Type:          h.Type,
-		Header:        h.Header,
-		Body:          h.Body,
-		AssertCorrect: correct,
-	}
+	return h.result(correct, "WhereNot(%q, %#v)", fieldName, value)
 }
 
 func (h HttpJson) WhereGte(fieldName string, value interface{}) HttpJson {
 	var correct bool
-	if h.Type == "body" && h.AssertCorrect {
-		f := strings.Split(fieldName, ".")
-		t := validation.GetBodyType(h.Body)
-
-		if t == "" {
-			panic("cannot read the response body")
-		} else if t == "object" {
-			if len(f) == 1 {
-				b := h.Body.(map[string]interface{})
-				for key, val := range b {
-					if key == fieldName {
-						vType := validation.GetValueType(value)
-						correct = validation.EqualValueWithOP(value, val, "gte", vType)
-
-						break
-					}
-				}
-			} else {
-				v := filter.Find(fieldName, h.Body)
-
-				vType := validation.GetValueType(value)
-				correct = validation.EqualValueWithOP(value, v, "gte", vType)
-			}
-		} else {
-			panic("body should be JSON object")
+	if (h.Type == "header" || h.Type == "query") && h.evaluate() {
+		var err error
+		if correct, err = h.compareText(fieldName, "gte", value); err != nil {
+			return h.result(false, "WhereGte(%q, %#v): %s", fieldName, value, err)
 		}
+	} else if h.Type == "body" && h.evaluate() {
+		v, ok, err := h.bodyValue(fieldName)
+		if err != nil {
+			return h.bodyFailure(err, "WhereGte(%q, %#v)", fieldName, value)
+		}
+		correct = ok && validation.CompareValues(v, value, "gte")
 	}
 
-	return HttpJson{
-		Type:          h.Type,
-		Header:        h.Header,
-		Body:          h.Body,
-		AssertCorrect: correct,
-	}
+	return h.result(correct, "WhereGte(%q, %#v)", fieldName, value)
 }
 
 func (h HttpJson) WhereGt(fieldName string, value interface{}) HttpJson {
 	var correct bool
-	if h.Type == "body" && h.AssertCorrect {
-		f := strings.Split(fieldName, ".")
-		t := validation.GetBodyType(h.Body)
-
-		if t == "" {
-			panic("cannot read the response body")
-		} else if t == "object" {
-			if len(f) == 1 {
-				b := h.Body.(map[string]interface{})
-				for key, val := range b {
-					if key == fieldName {
-						vType := validation.GetValueType(value)
-						correct = validation.EqualValueWithOP(value, val, "gt", vType)
-
-						break
-					}
-				}
-			} else {
-				v := filter.Find(fieldName, h.Body)
-
-				vType := validation.GetValueType(value)
-				correct = validation.EqualValueWithOP(value, v, "gt", vType)
-			}
-		} else {
-			panic("body should be JSON object")
+	if (h.Type == "header" || h.Type == "query") && h.evaluate() {
+		var err error
+		if correct, err = h.compareText(fieldName, "gt", value); err != nil {
+			return h.result(false, "WhereGt(%q, %#v): %s", fieldName, value, err)
+		}
+	} else if h.Type == "body" && h.evaluate() {
+		v, ok, err := h.bodyValue(fieldName)
+		if err != nil {
+			return h.bodyFailure(err, "WhereGt(%q, %#v)", fieldName, value)
 		}
+		correct = ok && validation.CompareValues(v, value, "gt")
 	}
 
-	return HttpJson{
-		Type:          h.Type,
-		Header:        h.Header,
-		Body:          h.Body,
-		AssertCorrect: correct,
-	}
+	return h.result(correct, "WhereGt(%q, %#v)", fieldName, value)
 }
 
 func (h HttpJson) WhereLte(fieldName string, value interface{}) HttpJson {
 	var correct bool
-	if h.Type == "body" && h.AssertCorrect {
-		f := strings.Split(fieldName, ".")
-		t := validation.GetBodyType(h.Body)
-
-		if t == "" {
-			panic("cannot read the response body")
-		} else if t == "object" {
-			if len(f) == 1 {
-				b := h.Body.(map[string]interface{})
-				for key, val := range b {
-					if key == fieldName {
-						vType := validation.GetValueType(value)
-						correct = validation.EqualValueWithOP(value, val, "lte", vType)
-
-						break
-					}
-				}
-			} else {
-				v := filter.Find(fieldName, h.Body)
-
-				vType := validation.GetValueType(value)
-				correct = validation.EqualValueWithOP(value, v, "lte", vType)
-			}
-		} else {
-			panic("body should be JSON object")
+	if (h.Type == "header" || h.Type == "query") && h.evaluate() {
+		var err error
+		if correct, err = h.compareText(fieldName, "lte", value); err != nil {
+			return h.result(false, "WhereLte(%q, %#v): %s", fieldName, value, err)
+		}
+	} else if h.Type == "body" && h.evaluate() {
+		v, ok, err := h.bodyValue(fieldName)
+		if err != nil {
+			return h.bodyFailure(err, "WhereLte(%q, %#v)", fieldName, value)
 		}
+		correct = ok && validation.CompareValues(v, value, "lte")
 	}
 
-	return HttpJson{
-		Type:          h.Type,
-		Header:        h.Header,
-		Body:          h.Body,
-		AssertCorrect: correct,
-	}
+	return h.result(correct, "WhereLte(%q, %#v)", fieldName, value)
 }
 
 func (h HttpJson) WhereLt(fieldName string, value interface{}) HttpJson {
 	var correct bool
-	if h.Type == "body" && h.AssertCorrect {
-		f := strings.Split(fieldName, ".")
-		t := validation.GetBodyType(h.Body)
-
-		if t == "" {
-			panic("cannot read the response body")
-		} else if t == "object" {
-			if len(f) == 1 {
-				b := h.Body.(map[string]interface{})
-				for key, val := range b {
-					if key == fieldName {
-						vType := validation.GetValueType(value)
-						correct = validation.EqualValueWithOP(value, val, "lt", vType)
-
-						break
-					}
-				}
-			} else {
-				v := filter.Find(fieldName, h.Body)
-
-				vType := validation.GetValueType(value)
-				correct = validation.EqualValueWithOP(value, v, "lt", vType)
-			}
-		} else {
-			panic("body should be JSON object")
+	if (h.Type == "header" || h.Type == "query") && h.evaluate() {
+		var err error
+		if correct, err = h.compareText(fieldName, "lt", value); err != nil {
+			return h.result(false, "WhereLt(%q, %#v): %s", fieldName, value, err)
+		}
+	} else if h.Type == "body" && h.evaluate() {
+		v, ok, err := h.bodyValue(fieldName)
+		if err != nil {
+			return h.bodyFailure(err, "WhereLt(%q, %#v)", fieldName, value)
 		}
+		correct = ok && validation.CompareValues(v, value, "lt")
 	}
 
-	return HttpJson{
-		Type:          h.Type,
-		Header:        h.Header,
-		Body:          h.Body,
-		AssertCorrect: correct,
-	}
+	return h.result(correct, "WhereLt(%q, %#v)", fieldName, value)
 }
 
 func (h HttpJson) WhereType(fieldName, valueType string) HttpJson {
 	var correct bool
-	if h.Type == "header" && h.AssertCorrect {
+	if h.Type == "header" && h.evaluate() {
 		hVal := h.Header.Get(fieldName)
 		t := validation.GetValueType(hVal)
 
 		correct = t == valueType
-	} else if h.Type == "body" && h.AssertCorrect {
-		f := strings.Split(fieldName, ".")
-		t := validation.GetBodyType(h.Body)
+	} else if h.Type == "query" && h.evaluate() {
+		v, ok := queryValue(h.Query, fieldName)
+		correct = ok && validation.GetValueType(v) == valueType
+	} else if h.Type == "body" && h.evaluate() {
+		v, ok, err := h.bodyValue(fieldName)
+		if err != nil {
+			return h.bodyFailure(err, "WhereType(%q, %q)", fieldName, valueType)
+		}
+		correct = ok && validation.GetValueType(v) == valueType
+	}
 
-		if t == "" {
-			panic("cannot read the response body")
-		} else if t == "object" {
-			if len(f) == 1 {
-				b := h.Body.(map[string]interface{})
-				for key, val := range b {
-					if key == fieldName {
-						t := validation.GetValueType(val)
-						correct = t == valueType
+	return h.result(correct, "WhereType(%q, %q)", fieldName, valueType)
+}
 
-						break
-					}
-				}
-			} else {
-				v := filter.Find(fieldName, h.Body)
-				t = validation.GetValueType(v)
+func (h HttpJson) bodyValue(fieldName string) (interface{}, bool, error) {
+	if err := h.bodyError(true); err != nil {
+		return nil, false, err
+	}
 
-				correct = t == valueType
-			}
-		} else {
-			panic("body should be JSON object")
-		}
+	v := filter.Find(fieldName, h.Body)
+	return v, v != nil, nil
+}
+
+func (h HttpJson) bodyError(object bool) error {
+	t := validation.GetBodyType(h.Body)
+
+	if t == "" {
+		return errors.New("cannot read the response body")
+	} else if object && t != "object" {
+		return errors.New("body should be JSON object")
 	}
 
-	return HttpJson{
-		Type:          h.Type,
-		Header:        h.Header,
-		Body:          h.Body,
-		AssertCorrect: correct,
+	return nil
+}
+
+func (h HttpJson) bodyFailure(err error, format string, args ...interface{}) HttpJson {
+	if !h.SoftMode {
+		panic(err.Error())
+	}
+
+	return h.result(false, format+": %s", append(args, err)...)
+}
+
+func equalValue(v, value interface{}) bool {
+	vType := validation.GetValueType(value)
+	if (vType == "string" || vType == "bool") && validation.GetValueType(v) != vType {
+		return false
 	}
+
+	return validation.EqualValue(value, v, vType)
 }
 
 func (h HttpJson) Check() bool {
 	return h.AssertCorrect
 }
+
+func (h HttpJson) Report() string {
+	report := strings.Join(h.Failures, "\n")
+	if report != "" && h.Resp != nil && h.Resp.Request != nil {
+		report += "\n" + requestSummary(h.Resp.Request)
+	}
+
+	return report
+}
+
+func (h HttpJson) Soft() HttpJson {
+	h.SoftMode = true
+	return h
+}
+
+func (h HttpJson) evaluate() bool {
+	return h.AssertCorrect || h.SoftMode
+}
+
+func (h HttpJson) result(correct bool, format string, args ...interface{}) HttpJson {
+	if h.evaluate() && !correct {
+		failure := h.Type + ": " + fmt.Sprintf(format, args...)
+		h.Failures = append(h.Failures[:len(h.Failures):len(h.Failures)], failure)
+	}
+
+	h.AssertCorrect = h.AssertCorrect && correct
+	return h
+}
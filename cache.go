@@ -0,0 +1,150 @@
+package assert
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ohmymajo/http-assert/pkg/header"
+)
+
+func (h Http) AssertCache() HttpJson {
+	return h.AssertHeader()
+}
+
+func (h HttpJson) IsCacheable() HttpJson {
+	var correct bool
+	if h.Type == "header" && h.evaluate() {
+		d := cacheDirectives(h.Header)
+
+		_, noStore := d["no-store"]
+		_, private := d["private"]
+
+		if !noStore && !private {
+			if sMaxAge, ok := directiveDuration(d, "s-maxage"); ok {
+				correct = sMaxAge > 0
+			} else if maxAge, ok := directiveDuration(d, "max-age"); ok {
+				correct = maxAge > 0
+			} else {
+				_, public := d["public"]
+
+				expires, err := http.ParseTime(h.Header.Get("Expires"))
+				correct = public || err == nil && expires.After(time.Now())
+			}
+		}
+	}
+
+	return h.result(correct, "IsCacheable()")
+}
+
+func (h HttpJson) NotStored() HttpJson {
+	return h.HasCacheDirective("no-store")
+}
+
+func (h HttpJson) IsPrivate() HttpJson {
+	return h.HasCacheDirective("private")
+}
+
+func (h HttpJson) IsImmutable() HttpJson {
+	return h.HasCacheDirective("immutable")
+}
+
+func (h HttpJson) HasCacheDirective(directive string) HttpJson {
+	var correct bool
+	if h.Type == "header" && h.evaluate() {
+		_, correct = cacheDirectives(h.Header)[strings.ToLower(directive)]
+	}
+
+	return h.result(correct, "HasCacheDirective(%q)", directive)
+}
+
+func (h HttpJson) MaxAgeAtLeast(d time.Duration) HttpJson {
+	return h.cacheDuration("MaxAgeAtLeast", "max-age", "gte", d)
+}
+
+func (h HttpJson) MaxAgeAtMost(d time.Duration) HttpJson {
+	return h.cacheDuration("MaxAgeAtMost", "max-age", "lte", d)
+}
+
+func (h HttpJson) SharedMaxAgeAtLeast(d time.Duration) HttpJson {
+	return h.cacheDuration("SharedMaxAgeAtLeast", "s-maxage", "gte", d)
+}
+
+func (h HttpJson) StaleWhileRevalidateAtLeast(d time.Duration) HttpJson {
+	return h.cacheDuration("StaleWhileRevalidateAtLeast", "stale-while-revalidate", "gte", d)
+}
+
+func (h HttpJson) cacheDuration(name, directive, op string, d time.Duration) HttpJson {
+	var correct bool
+	if h.Type == "header" && h.evaluate() {
+		v, ok := directiveDuration(cacheDirectives(h.Header), directive)
+		if ok {
+			correct = (op == "gte" && v >= d) || (op == "lte" && v <= d)
+		}
+	}
+
+	return h.result(correct, "%s(%s)", name, d)
+}
+
+func (h HttpJson) ExpiresAfter(t time.Time) HttpJson {
+	var correct bool
+	if h.Type == "header" && h.evaluate() {
+		expires, err := http.ParseTime(h.Header.Get("Expires"))
+		correct = err == nil && expires.After(t)
+	}
+
+	return h.result(correct, "ExpiresAfter(%s)", t)
+}
+
+func (h HttpJson) AgeAtMost(d time.Duration) HttpJson {
+	var correct bool
+	if h.Type == "header" && h.evaluate() {
+		age, err := strconv.Atoi(strings.TrimSpace(h.Header.Get("Age")))
+		correct = err == nil && time.Duration(age)*time.Second <= d
+	}
+
+	return h.result(correct, "AgeAtMost(%s)", d)
+}
+
+func (h HttpJson) HasETag() HttpJson {
+	var correct bool
+	if h.Type == "header" && h.evaluate() {
+		etag := strings.TrimPrefix(h.Header.Get("ETag"), "W/")
+		correct = len(etag) >= 2 && strings.HasPrefix(etag, `"`) && strings.HasSuffix(etag, `"`)
+	}
+
+	return h.result(correct, "HasETag()")
+}
+
+func (h HttpJson) HasLastModified() HttpJson {
+	var correct bool
+	if h.Type == "header" && h.evaluate() {
+		modified, err := http.ParseTime(h.Header.Get("Last-Modified"))
+		correct = err == nil && !modified.After(time.Now())
+	}
+
+	return h.result(correct, "HasLastModified()")
+}
+
+func (h HttpJson) VaryIncludes(fieldName string) HttpJson {
+	return h.HasHeaderToken("Vary", fieldName)
+}
+
+func cacheDirectives(h *http.Header) map[string]string {
+	return header.ParseDirectives(h.Values("Cache-Control"))
+}
+
+func directiveDuration(directives map[string]string, name string) (time.Duration, bool) {
+	v, ok := directives[name]
+	if !ok {
+		return 0, false
+	}
+
+	seconds, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, false
+	}
+
+	return time.Duration(seconds) * time.Second, true
+}
@@ -0,0 +1,167 @@
+package assert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/ohmymajo/http-assert/pkg/filter"
+)
+
+type Vars struct {
+	mu     sync.Mutex
+	values map[string]interface{}
+}
+
+func NewVars() *Vars {
+	return &Vars{values: map[string]interface{}{}}
+}
+
+func (v *Vars) Set(name string, value interface{}) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.values[name] = value
+}
+
+func (v *Vars) Get(name string) (interface{}, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	value, ok := v.values[name]
+	return value, ok
+}
+
+func (v *Vars) Interpolate(text string) string {
+	if v == nil || !strings.Contains(text, "{{") {
+		return text
+	}
+
+	t, err := template.New("vars").Option("missingkey=error").Parse(text)
+	if err != nil {
+		panic(fmt.Sprintf("cannot parse template %q", text))
+	}
+
+	v.mu.Lock()
+	data := make(map[string]interface{}, len(v.values))
+	for name, value := range v.values {
+		data[name] = value
+	}
+	v.mu.Unlock()
+
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		panic(fmt.Sprintf("cannot render template %q: %s", text, err))
+	}
+
+	return b.String()
+}
+
+var wholeVar = regexp.MustCompile(`^\{\{\s*\.(\w+)\s*\}\}$`)
+
+func (v *Vars) resolve(text string) interface{} {
+	if m := wholeVar.FindStringSubmatch(text); v != nil && m != nil {
+		if value, ok := v.Get(m[1]); ok {
+			return value
+		}
+	}
+
+	return v.Interpolate(text)
+}
+
+func (v *Vars) interpolateJSON(body []byte) []byte {
+	if v == nil || !bytes.Contains(body, []byte("{{")) {
+		return body
+	}
+
+	var data interface{}
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	if err := d.Decode(&data); err != nil {
+		panic("cannot decode json data")
+	}
+
+	b, err := json.Marshal(v.interpolateValue(data))
+	if err != nil {
+		panic("cannot encode json data")
+	}
+
+	return b
+}
+
+func (v *Vars) interpolateValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case string:
+		return v.Interpolate(value)
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			m[v.Interpolate(key)] = v.interpolateValue(item)
+		}
+
+		return m
+	case []interface{}:
+		for i, item := range value {
+			value[i] = v.interpolateValue(item)
+		}
+	}
+
+	return value
+}
+
+func (h HttpJson) Capture(fieldName string, dst interface{}) HttpJson {
+	if rv := reflect.ValueOf(dst); rv.Kind() != reflect.Ptr || rv.IsNil() {
+		panic("capture destination must be a non-nil pointer")
+	}
+
+	var correct bool
+	if h.Type == "header" && h.evaluate() {
+		if v := h.Header.Get(fieldName); v != "" {
+			if p, ok := dst.(*string); ok {
+				*p = v
+				correct = true
+			} else {
+				correct = json.Unmarshal([]byte(v), dst) == nil || assign(v, dst)
+			}
+		}
+	} else if h.Type == "body" && h.evaluate() {
+		if err := h.bodyError(false); err != nil {
+			return h.bodyFailure(err, "Capture(%q)", fieldName)
+		}
+
+		if v := filter.Find(fieldName, h.Body); v != nil {
+			correct = assign(v, dst)
+		}
+	}
+
+	return h.result(correct, "Capture(%q)", fieldName)
+}
+
+func (h HttpJson) CaptureAs(vars *Vars, name, fieldName string) HttpJson {
+	var v interface{}
+	h = h.Capture(fieldName, &v)
+	if v != nil {
+		vars.Set(name, v)
+	}
+
+	return h
+}
+
+func assign(v, dst interface{}) bool {
+	if p, ok := dst.(*interface{}); ok {
+		*p = v
+		return true
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return false
+	}
+
+	return json.Unmarshal(b, dst) == nil
+}
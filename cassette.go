@@ -0,0 +1,394 @@
+package assert
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	CassetteRecord = "record"
+	CassetteReplay = "replay"
+
+	redacted = "[REDACTED]"
+)
+
+var DefaultRedact = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+type Cassette struct {
+	Path     string
+	Mode     string
+	Base     http.RoundTripper
+	Matchers []CassetteMatcher
+	Redact   []string
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+type Interaction struct {
+	Request  CassetteRequest  `json:"request" yaml:"request"`
+	Response CassetteResponse `json:"response" yaml:"response"`
+}
+
+type CassetteRequest struct {
+	Method   string      `json:"method" yaml:"method"`
+	URL      string      `json:"url" yaml:"url"`
+	Header   http.Header `json:"header,omitempty" yaml:"header,omitempty"`
+	Body     string      `json:"body,omitempty" yaml:"body,omitempty"`
+	Encoding string      `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+}
+
+type CassetteResponse struct {
+	Status   int         `json:"status" yaml:"status"`
+	Header   http.Header `json:"header,omitempty" yaml:"header,omitempty"`
+	Body     string      `json:"body,omitempty" yaml:"body,omitempty"`
+	Encoding string      `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+}
+
+type CassetteMatcher func(actual, recorded CassetteRequest) bool
+
+func MatchMethod(actual, recorded CassetteRequest) bool {
+	return strings.EqualFold(actual.Method, recorded.Method)
+}
+
+func MatchURL(actual, recorded CassetteRequest) bool {
+	a, errA := url.Parse(actual.URL)
+	b, errB := url.Parse(recorded.URL)
+	if errA != nil || errB != nil {
+		return actual.URL == recorded.URL
+	}
+
+	return a.Scheme == b.Scheme && a.Host == b.Host && a.Path == b.Path && a.Query().Encode() == b.Query().Encode()
+}
+
+func MatchBody(actual, recorded CassetteRequest) bool {
+	var a, b interface{}
+	if json.Unmarshal([]byte(actual.Body), &a) == nil && json.Unmarshal([]byte(recorded.Body), &b) == nil {
+		return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
+	}
+
+	return actual.Body == recorded.Body
+}
+
+func NewCassette(path, mode string) (*Cassette, error) {
+	c := &Cassette{
+		Path:     path,
+		Mode:     mode,
+		Matchers: []CassetteMatcher{MatchMethod, MatchURL},
+		Redact:   append([]string(nil), DefaultRedact...),
+	}
+
+	switch mode {
+	case CassetteRecord:
+	case CassetteReplay:
+		if err := c.load(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown cassette mode %q", mode)
+	}
+
+	return c, nil
+}
+
+func (c *Cassette) Client() *http.Client {
+	return &http.Client{Transport: c}
+}
+
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]Interaction(nil), c.interactions...)
+}
+
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	reqBody, reqEncoding := encodeCassetteBody(body)
+	recorded := c.redactRequest(CassetteRequest{
+		Method:   req.Method,
+		URL:      req.URL.String(),
+		Header:   req.Header.Clone(),
+		Body:     reqBody,
+		Encoding: reqEncoding,
+	})
+
+	if c.Mode == CassetteReplay {
+		return c.replay(req, recorded)
+	}
+
+	base := c.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Request == nil {
+		resp.Request = req
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(content))
+
+	respBody, respEncoding := encodeCassetteBody(content)
+	if respEncoding == "" {
+		respBody = c.redactBody(respBody)
+	}
+
+	c.mu.Lock()
+	c.interactions = append(c.interactions, Interaction{
+		Request: recorded,
+		Response: CassetteResponse{
+			Status:   resp.StatusCode,
+			Header:   c.redactHeader(resp.Header.Clone()),
+			Body:     respBody,
+			Encoding: respEncoding,
+		},
+	})
+	c.mu.Unlock()
+
+	return resp, nil
+}
+
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	interactions := c.interactions
+	c.mu.Unlock()
+
+	if interactions == nil {
+		interactions = []Interaction{}
+	}
+
+	var b []byte
+	var err error
+	if isYAML(c.Path) {
+		b, err = yaml.Marshal(interactions)
+	} else {
+		b, err = json.MarshalIndent(interactions, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("cannot encode cassette %s: %s", c.Path, err)
+	}
+
+	if dir := filepath.Dir(c.Path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(c.Path, b, 0o644)
+}
+
+func (c *Cassette) Rewind() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.used = make([]bool, len(c.interactions))
+}
+
+func (c *Cassette) load() error {
+	b, err := os.ReadFile(c.Path)
+	if err != nil {
+		return err
+	}
+
+	if isYAML(c.Path) {
+		err = yaml.Unmarshal(b, &c.interactions)
+	} else {
+		err = json.Unmarshal(b, &c.interactions)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot parse cassette %s: %s", c.Path, err)
+	}
+
+	c.used = make([]bool, len(c.interactions))
+	return nil
+}
+
+func (c *Cassette) replay(req *http.Request, actual CassetteRequest) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, interaction := range c.interactions {
+		if c.used[i] || !c.matches(actual, interaction.Request) {
+			continue
+		}
+
+		r := interaction.Response
+		body, err := decodeCassetteBody(r.Body, r.Encoding)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode cassette %s response body: %s", c.Path, err)
+		}
+
+		c.used[i] = true
+
+		header := r.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+
+		return &http.Response{
+			Status:        strconv.Itoa(r.Status) + " " + http.StatusText(r.Status),
+			StatusCode:    r.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("cassette %s has no interaction for %s %s", c.Path, actual.Method, actual.URL)
+}
+
+func (c *Cassette) matches(actual, recorded CassetteRequest) bool {
+	for _, match := range c.Matchers {
+		if !match(actual, recorded) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *Cassette) redactRequest(r CassetteRequest) CassetteRequest {
+	r.Header = c.redactHeader(r.Header)
+	if r.Encoding == "" {
+		r.Body = c.redactBody(r.Body)
+	}
+
+	if u, err := url.Parse(r.URL); err == nil {
+		q := u.Query()
+		for key := range q {
+			if c.secret(key) {
+				q.Set(key, redacted)
+			}
+		}
+
+		if u.User != nil {
+			u.User = url.User(redacted)
+		}
+
+		u.RawQuery = q.Encode()
+		r.URL = u.String()
+	}
+
+	return r
+}
+
+func (c *Cassette) redactHeader(h http.Header) http.Header {
+	for key := range h {
+		if c.secret(key) {
+			h[key] = []string{redacted}
+		}
+	}
+
+	return h
+}
+
+func (c *Cassette) redactBody(body string) string {
+	if len(c.Redact) == 0 {
+		return body
+	}
+
+	var v interface{}
+	d := json.NewDecoder(strings.NewReader(body))
+	d.UseNumber()
+	if d.Decode(&v) != nil || d.More() || !c.redactValue(v) {
+		return body
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+
+	return string(b)
+}
+
+func (c *Cassette) redactValue(v interface{}) bool {
+	var changed bool
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if c.secret(key) {
+				v[key] = redacted
+				changed = true
+			} else if c.redactValue(value) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if c.redactValue(item) {
+				changed = true
+			}
+		}
+	}
+
+	return changed
+}
+
+func (c *Cassette) secret(name string) bool {
+	for _, s := range c.Redact {
+		if strings.EqualFold(s, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func encodeCassetteBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+func decodeCassetteBody(body, encoding string) ([]byte, error) {
+	switch encoding {
+	case "":
+		return []byte(body), nil
+	case "base64":
+		return base64.StdEncoding.DecodeString(body)
+	}
+
+	return nil, fmt.Errorf("unknown encoding %q", encoding)
+}
+
+func isYAML(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == ".yaml" || ext == ".yml"
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	assert "github.com/ohmymajo/http-assert"
+)
+
+const (
+	exitPass  = 0
+	exitFail  = 1
+	exitUsage = 2
+)
+
+type job struct {
+	spec  *assert.Spec
+	file  string
+	cases []assert.SpecCase
+}
+
+type outcome struct {
+	file   string
+	result assert.SpecResult
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("http-assert", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: http-assert [flags] spec.yaml [spec.json ...]")
+		flags.PrintDefaults()
+	}
+
+	baseURL := flags.String("base-url", "", "base URL overriding the spec and environment")
+	env := flags.String("env", "", "environment to select from the spec files")
+	filter := flags.String("filter", "", "only run cases whose name matches this regular expression")
+	failFast := flags.Bool("fail-fast", false, "stop after the first failing case")
+	parallel := flags.Int("parallel", 1, "number of spec files to run concurrently; cases within a file run in order")
+	timeout := flags.Duration("timeout", 30*time.Second, "timeout for each request")
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if flags.NArg() == 0 || *parallel < 1 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	var pattern *regexp.Regexp
+	if *filter != "" {
+		var err error
+		if pattern, err = regexp.Compile(*filter); err != nil {
+			fmt.Fprintf(stderr, "invalid filter: %s\n", err)
+			return exitUsage
+		}
+	}
+
+	var jobs []job
+	var total int
+	for _, file := range flags.Args() {
+		spec, err := assert.LoadSpec(file)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+
+		if *env != "" {
+			if err := spec.Use(*env); err != nil {
+				fmt.Fprintf(stderr, "%s: %s\n", file, err)
+				return exitUsage
+			}
+		}
+
+		if *baseURL != "" {
+			spec.BaseURL = *baseURL
+		}
+
+		var cases []assert.SpecCase
+		for _, c := range spec.Cases {
+			if pattern == nil || pattern.MatchString(c.Name) {
+				cases = append(cases, c)
+			}
+		}
+
+		if len(cases) > 0 {
+			jobs = append(jobs, job{spec: spec, file: file, cases: cases})
+			total += len(cases)
+		}
+	}
+
+	if total == 0 {
+		fmt.Fprintln(stderr, "no cases to run")
+		return exitUsage
+	}
+
+	client := &http.Client{Timeout: *timeout}
+	results := make(chan outcome)
+	queue := make(chan job)
+	stop := make(chan struct{})
+
+	var once sync.Once
+	var wg sync.WaitGroup
+	for i := 0; i < *parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range queue {
+				for _, c := range j.cases {
+					if stopped(stop) {
+						break
+					}
+
+					r := j.spec.Run(c, client)
+					if !r.Passed && *failFast {
+						once.Do(func() { close(stop) })
+					}
+
+					results <- outcome{file: j.file, result: r}
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(queue)
+		for _, j := range jobs {
+			select {
+			case queue <- j:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var passed, failed int
+	for o := range results {
+		fmt.Fprintln(stdout, format(o.file, o.result))
+
+		if o.result.Passed {
+			passed++
+		} else {
+			failed++
+		}
+	}
+
+	skipped := total - passed - failed
+	fmt.Fprintf(stdout, "\n%d passed, %d failed, %d skipped\n", passed, failed, skipped)
+
+	if failed > 0 {
+		return exitFail
+	}
+
+	return exitPass
+}
+
+func stopped(stop chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func format(file string, r assert.SpecResult) string {
+	status := "PASS"
+	if !r.Passed {
+		status = "FAIL"
+	}
+
+	line := fmt.Sprintf("%s %s: %s (%s)", status, file, r.Name, r.Duration.Round(time.Millisecond))
+	if r.Passed {
+		return line
+	}
+
+	return line + "\n    " + strings.ReplaceAll(r.Report, "\n", "\n    ")
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const spec = `
+baseURL: http://127.0.0.1:1
+environments:
+  local:
+    baseURL: {{URL}}
+cases:
+  - name: user ok
+    request: {path: /users/1}
+    expect:
+      status: 200
+      body:
+        - {path: id, value: 1}
+  - name: user broken
+    request: {path: /broken}
+    expect: {status: 200}
+  - name: user slow
+    request: {path: /slow}
+    expect: {status: 200}
+  - name: user slow again
+    request: {path: /slow}
+    expect: {status: 200}
+`
+
+const captureSpec = `
+baseURL: {{URL}}
+cases:
+  - name: create user
+    request: {method: POST, path: /users}
+    expect: {status: 201}
+    capture: {id: id}
+  - name: fetch created user
+    request: {path: "/users/{{.id}}"}
+    expect:
+      status: 200
+      body:
+        - {path: id, value: 42}
+`
+
+func testServer(active, peak *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(active, 1)
+		defer atomic.AddInt32(active, -1)
+
+		for {
+			p := atomic.LoadInt32(peak)
+			if n <= p || atomic.CompareAndSwapInt32(peak, p, n) {
+				break
+			}
+		}
+
+		switch r.URL.Path {
+		case "/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/slow":
+			time.Sleep(100 * time.Millisecond)
+		case "/users":
+			time.Sleep(50 * time.Millisecond)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 42}`))
+		case "/users/1", "/users/42":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id": ` + strings.TrimPrefix(r.URL.Path, "/users/") + `}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func runCLI(t *testing.T, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func writeSpec(t *testing.T, url string) string {
+	return writeSpecText(t, spec, url)
+}
+
+func writeSpecText(t *testing.T, text, url string) string {
+	path := filepath.Join(t.TempDir(), "api.yaml")
+	if err := os.WriteFile(path, []byte(strings.ReplaceAll(text, "{{URL}}", url)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestRunPass(t *testing.T) {
+	var active, peak int32
+	server := testServer(&active, &peak)
+	defer server.Close()
+
+	path := writeSpec(t, server.URL)
+
+	code, stdout, _ := runCLI(t, "--env", "local", "--filter", "ok$", path)
+	if code != exitPass || !strings.Contains(stdout, "PASS "+path+": user ok") || !strings.Contains(stdout, "1 passed, 0 failed, 0 skipped") {
+		t.Errorf("exit %d\n%s", code, stdout)
+	}
+
+	code, stdout, _ = runCLI(t, "--base-url", server.URL, "--filter", "ok$", path)
+	if code != exitPass || !strings.Contains(stdout, "1 passed") {
+		t.Errorf("base url: exit %d\n%s", code, stdout)
+	}
+}
+
+func TestRunFail(t *testing.T) {
+	var active, peak int32
+	server := testServer(&active, &peak)
+	defer server.Close()
+
+	code, stdout, _ := runCLI(t, "--env", "local", writeSpec(t, server.URL))
+	if code != exitFail || !strings.Contains(stdout, "3 passed, 1 failed, 0 skipped") {
+		t.Errorf("exit %d\n%s", code, stdout)
+	}
+
+	if !strings.Contains(stdout, "FAIL") || !strings.Contains(stdout, "    status: expected 200, got 500 Internal Server Error") {
+		t.Error(stdout)
+	}
+}
+
+func TestRunFailFast(t *testing.T) {
+	var active, peak int32
+	server := testServer(&active, &peak)
+	defer server.Close()
+
+	code, stdout, _ := runCLI(t, "--env", "local", "--fail-fast", writeSpec(t, server.URL))
+	if code != exitFail || !strings.Contains(stdout, "1 passed, 1 failed, 2 skipped") || strings.Contains(stdout, "user slow") {
+		t.Errorf("exit %d\n%s", code, stdout)
+	}
+}
+
+func TestRunParallel(t *testing.T) {
+	var active, peak int32
+	server := testServer(&active, &peak)
+	defer server.Close()
+
+	code, stdout, _ := runCLI(t, "--env", "local", "--parallel", "2", "--filter", "slow$", writeSpec(t, server.URL), writeSpec(t, server.URL))
+	if code != exitPass || !strings.Contains(stdout, "2 passed") {
+		t.Errorf("exit %d\n%s", code, stdout)
+	}
+
+	if atomic.LoadInt32(&peak) < 2 {
+		t.Error("spec files should run concurrently")
+	}
+
+	atomic.StoreInt32(&peak, 0)
+	runCLI(t, "--env", "local", "--parallel", "2", "--filter", "slow", writeSpec(t, server.URL))
+	if atomic.LoadInt32(&peak) != 1 {
+		t.Error("cases within a spec file should run one at a time")
+	}
+
+	atomic.StoreInt32(&peak, 0)
+	runCLI(t, "--env", "local", "--filter", "slow$", writeSpec(t, server.URL), writeSpec(t, server.URL))
+	if atomic.LoadInt32(&peak) != 1 {
+		t.Error("spec files should run one at a time by default")
+	}
+}
+
+func TestRunParallelCaptures(t *testing.T) {
+	var active, peak int32
+	server := testServer(&active, &peak)
+	defer server.Close()
+
+	first := writeSpecText(t, captureSpec, server.URL)
+	second := writeSpecText(t, captureSpec, server.URL)
+
+	code, stdout, _ := runCLI(t, "--parallel", "4", first, second)
+	if code != exitPass || !strings.Contains(stdout, "4 passed, 0 failed, 0 skipped") {
+		t.Errorf("exit %d\n%s", code, stdout)
+	}
+}
+
+func TestRunUsageErrors(t *testing.T) {
+	var active, peak int32
+	server := testServer(&active, &peak)
+	defer server.Close()
+
+	path := writeSpec(t, server.URL)
+
+	for name, args := range map[string][]string{
+		"no spec":         {},
+		"unknown flag":    {"--verbose", path},
+		"invalid filter":  {"--filter", "(", path},
+		"no matches":      {"--filter", "nothing", path},
+		"unknown env":     {"--env", "prod", path},
+		"missing file":    {filepath.Join(t.TempDir(), "missing.yaml")},
+		"invalid workers": {"--parallel", "0", path},
+	} {
+		if code, _, stderr := runCLI(t, args...); code != exitUsage || stderr == "" {
+			t.Errorf("%s: exit %d", name, code)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package assert
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ohmymajo/http-assert/pkg/filter"
+	"github.com/ohmymajo/http-assert/pkg/validation"
+)
+
+type HttpWhen struct {
+	HttpJson
+	Matched bool
+}
+
+func (h HttpJson) When(fieldName string, value interface{}) HttpWhen {
+	var matched bool
+	if h.Type == "header" && h.evaluate() {
+		matched = matchValue(h.Header.Get(fieldName), value)
+	} else if h.Type == "body" && h.evaluate() {
+		if err := h.bodyError(false); err != nil {
+			return HttpWhen{HttpJson: h.bodyFailure(err, "When(%q, %#v)", fieldName, value)}
+		}
+
+		v := findValue(fieldName, h.Body)
+		if v != nil {
+			matched = matchValue(v, value)
+		}
+	}
+
+	return HttpWhen{
+		HttpJson: h,
+		Matched:  matched,
+	}
+}
+
+func (w HttpWhen) Then(fn func(HttpJson) HttpJson) HttpWhen {
+	if w.Matched && w.evaluate() {
+		w.HttpJson = fn(w.HttpJson)
+	}
+
+	return w
+}
+
+func (w HttpWhen) Otherwise(fn func(HttpJson) HttpJson) HttpJson {
+	if !w.Matched && w.evaluate() {
+		return fn(w.HttpJson)
+	}
+
+	return w.HttpJson
+}
+
+func (h HttpJson) Each(fieldName string, fn func(HttpJson) HttpJson) HttpJson {
+	var correct bool
+	if h.Type == "body" && h.evaluate() {
+		if err := h.bodyError(false); err != nil {
+			return h.bodyFailure(err, "Each(%q)", fieldName)
+		}
+
+		var failures []string
+		if len(filter.FindAll(fieldName, h.Body)) > 0 {
+			correct = true
+			for i, item := range findValues(fieldName, h.Body) {
+				item := HttpJson{
+					Type:          h.Type,
+					Header:        h.Header,
+					Body:          item,
+					AssertCorrect: true,
+					Resp:          h.Resp,
+					SoftMode:      h.SoftMode,
+				}
+
+				if r := fn(item); !r.Check() {
+					correct = false
+					failures = append(failures, fmt.Sprintf("item %d: %s", i, strings.Join(r.Failures, "; ")))
+					if !h.SoftMode {
+						break
+					}
+				}
+			}
+		}
+
+		if len(failures) > 0 {
+			return h.result(correct, "Each(%q) %s", fieldName, strings.Join(failures, ", "))
+		}
+	}
+
+	return h.result(correct, "Each(%q)", fieldName)
+}
+
+func matchValue(v, value interface{}) bool {
+	if predicate, ok := value.(func(interface{}) bool); ok {
+		return predicate(v)
+	}
+
+	return validation.CompareValues(v, value, "eq")
+}
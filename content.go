@@ -0,0 +1,25 @@
+package assert
+
+import (
+	"fmt"
+
+	"github.com/ohmymajo/http-assert/pkg/header"
+)
+
+func (h Http) AssertContentType(mediaType string) HttpJson {
+	return h.AssertHeader().ContentType(mediaType)
+}
+
+func (h HttpJson) ContentType(mediaType string) HttpJson {
+	var correct bool
+	if h.Type == "header" && h.evaluate() {
+		ok, err := header.MatchMediaType(h.Header.Get("Content-Type"), mediaType)
+		if err != nil {
+			panic(fmt.Sprintf("cannot parse media type %q", mediaType))
+		}
+
+		correct = ok
+	}
+
+	return h.result(correct, "ContentType(%q)", mediaType)
+}
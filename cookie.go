@@ -0,0 +1,149 @@
+package assert
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+func (h Http) AssertCookies() HttpJson {
+	return HttpJson{
+		Type:          "cookie",
+		Header:        &h.Resp.Header,
+		AssertCorrect: true,
+		Resp:          h.Resp,
+	}
+}
+
+func (h HttpJson) CookiePath(name, path string) HttpJson {
+	var correct bool
+	if h.Type == "cookie" && h.evaluate() {
+		c := findCookie(h.Header, name)
+		correct = c != nil && c.Path == path
+	}
+
+	return h.result(correct, "CookiePath(%q, %q)", name, path)
+}
+
+func (h HttpJson) CookieDomain(name, domain string) HttpJson {
+	var correct bool
+	if h.Type == "cookie" && h.evaluate() {
+		c := findCookie(h.Header, name)
+		correct = c != nil && strings.EqualFold(strings.TrimPrefix(c.Domain, "."), strings.TrimPrefix(domain, "."))
+	}
+
+	return h.result(correct, "CookieDomain(%q, %q)", name, domain)
+}
+
+func (h HttpJson) CookieExpiresAfter(name string, t time.Time) HttpJson {
+	var correct bool
+	if h.Type == "cookie" && h.evaluate() {
+		c := findCookie(h.Header, name)
+		if c != nil && c.MaxAge > 0 {
+			correct = time.Now().Add(time.Duration(c.MaxAge) * time.Second).After(t)
+		} else if c != nil && c.MaxAge == 0 {
+			correct = c.Expires.After(t)
+		}
+	}
+
+	return h.result(correct, "CookieExpiresAfter(%q, %s)", name, t)
+}
+
+func (h HttpJson) CookieMaxAgeAtLeast(name string, d time.Duration) HttpJson {
+	var correct bool
+	if h.Type == "cookie" && h.evaluate() {
+		c := findCookie(h.Header, name)
+		correct = c != nil && c.MaxAge > 0 && time.Duration(c.MaxAge)*time.Second >= d
+	}
+
+	return h.result(correct, "CookieMaxAgeAtLeast(%q, %s)", name, d)
+}
+
+func (h HttpJson) CookieSecure(name string) HttpJson {
+	var correct bool
+	if h.Type == "cookie" && h.evaluate() {
+		c := findCookie(h.Header, name)
+		correct = c != nil && c.Secure
+	}
+
+	return h.result(correct, "CookieSecure(%q)", name)
+}
+
+func (h HttpJson) CookieHttpOnly(name string) HttpJson {
+	var correct bool
+	if h.Type == "cookie" && h.evaluate() {
+		c := findCookie(h.Header, name)
+		correct = c != nil && c.HttpOnly
+	}
+
+	return h.result(correct, "CookieHttpOnly(%q)", name)
+}
+
+func (h HttpJson) CookieSameSite(name, mode string) HttpJson {
+	var correct bool
+	if h.Type == "cookie" && h.evaluate() {
+		c := findCookie(h.Header, name)
+		correct = c != nil && strings.EqualFold(sameSite(c), mode)
+	}
+
+	return h.result(correct, "CookieSameSite(%q, %q)", name, mode)
+}
+
+func (h HttpJson) CookiePartitioned(name string) HttpJson {
+	var correct bool
+	if h.Type == "cookie" && h.evaluate() {
+		c := findCookie(h.Header, name)
+		correct = c != nil && hasCookieAttribute(c, "Partitioned")
+	}
+
+	return h.result(correct, "CookiePartitioned(%q)", name)
+}
+
+func (h HttpJson) SecureSessionCookie(name string) HttpJson {
+	var correct bool
+	if h.Type == "cookie" && h.evaluate() {
+		c := findCookie(h.Header, name)
+		if c != nil {
+			mode := sameSite(c)
+			correct = c.Secure && c.HttpOnly && (mode == "Lax" || mode == "Strict") && (c.Path == "" || c.Path == "/")
+		}
+	}
+
+	return h.result(correct, "SecureSessionCookie(%q)", name)
+}
+
+func findCookie(h *http.Header, name string) *http.Cookie {
+	resp := http.Response{Header: *h}
+	for _, c := range resp.Cookies() {
+		if c.Name == name {
+			return c
+		}
+	}
+
+	return nil
+}
+
+func sameSite(c *http.Cookie) string {
+	switch c.SameSite {
+	case http.SameSiteLaxMode:
+		return "Lax"
+	case http.SameSiteStrictMode:
+		return "Strict"
+	case http.SameSiteNoneMode:
+		return "None"
+	default:
+		return ""
+	}
+}
+
+func hasCookieAttribute(c *http.Cookie, attribute string) bool {
+	parts := strings.Split(c.Raw, ";")
+	for _, part := range parts[1:] {
+		name, _, _ := strings.Cut(part, "=")
+		if strings.EqualFold(strings.TrimSpace(name), attribute) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,112 @@
+package assert
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type CORSRequest struct {
+	Origin      string
+	Method      string
+	Headers     []string
+	Credentials bool
+}
+
+func (h Http) AssertCORS(req CORSRequest) HttpJson {
+	hj := h.AssertHeader()
+	return hj.ruleResult(corsRules(hj.Header, req))
+}
+
+func (h Http) AssertPreflight(req CORSRequest) HttpJson {
+	hj := h.AssertHeader()
+
+	var failures []string
+	if h.Resp.StatusCode < 200 || h.Resp.StatusCode > 299 {
+		failures = append(failures, fmt.Sprintf("preflight status must be 2xx, got %d", h.Resp.StatusCode))
+	}
+
+	failures = append(failures, corsRules(hj.Header, req)...)
+	failures = append(failures, preflightRules(hj.Header, req)...)
+	return hj.ruleResult(failures)
+}
+
+func (h HttpJson) ExposesHeader(fieldName string) HttpJson {
+	return h.HasHeaderToken("Access-Control-Expose-Headers", fieldName)
+}
+
+func (h HttpJson) CORSMaxAgeAtLeast(d time.Duration) HttpJson {
+	var correct bool
+	if h.Type == "header" && h.evaluate() {
+		seconds, err := strconv.Atoi(strings.TrimSpace(h.Header.Get("Access-Control-Max-Age")))
+		correct = err == nil && time.Duration(seconds)*time.Second >= d
+	}
+
+	return h.result(correct, "CORSMaxAgeAtLeast(%s)", d)
+}
+
+func corsRules(h *http.Header, req CORSRequest) []string {
+	origin := h.Get("Access-Control-Allow-Origin")
+	if origin == "" {
+		return []string{"Access-Control-Allow-Origin is missing"}
+	}
+
+	var failures []string
+	if origin == "*" {
+		if req.Credentials {
+			failures = append(failures, "Access-Control-Allow-Origin cannot be * for credentialed requests")
+		}
+	} else {
+		if origin != req.Origin {
+			failures = append(failures, fmt.Sprintf("Access-Control-Allow-Origin must be %q, got %q", req.Origin, origin))
+		}
+
+		if !containsFold(headerTokens(h, "Vary"), "Origin") && !containsFold(headerTokens(h, "Vary"), "*") {
+			failures = append(failures, "Vary must include Origin when Access-Control-Allow-Origin echoes the origin")
+		}
+	}
+
+	if req.Credentials && h.Get("Access-Control-Allow-Credentials") != "true" {
+		failures = append(failures, fmt.Sprintf("Access-Control-Allow-Credentials must be true, got %q", h.Get("Access-Control-Allow-Credentials")))
+	}
+
+	return failures
+}
+
+func preflightRules(h *http.Header, req CORSRequest) []string {
+	var failures []string
+
+	methods := headerTokens(h, "Access-Control-Allow-Methods")
+	if req.Method != "" && !isSafelistedMethod(req.Method) && !allowsToken(methods, req.Method, req.Credentials) {
+		failures = append(failures, fmt.Sprintf("Access-Control-Allow-Methods must allow %s, got %q", req.Method, h.Get("Access-Control-Allow-Methods")))
+	}
+
+	headers := headerTokens(h, "Access-Control-Allow-Headers")
+	for _, name := range req.Headers {
+		if !allowsToken(headers, name, req.Credentials) {
+			failures = append(failures, fmt.Sprintf("Access-Control-Allow-Headers must allow %s, got %q", name, h.Get("Access-Control-Allow-Headers")))
+		}
+	}
+
+	if maxAge := h.Get("Access-Control-Max-Age"); maxAge != "" {
+		if _, err := strconv.Atoi(strings.TrimSpace(maxAge)); err != nil {
+			failures = append(failures, fmt.Sprintf("Access-Control-Max-Age is invalid: %q", maxAge))
+		}
+	}
+
+	return failures
+}
+
+func allowsToken(tokens []string, token string, credentials bool) bool {
+	if containsFold(tokens, token) {
+		return true
+	}
+
+	return !credentials && containsFold(tokens, "*") && !strings.EqualFold(token, "Authorization")
+}
+
+func isSafelistedMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodPost
+}
@@ -0,0 +1,35 @@
+package assert
+
+import (
+	"net/http"
+
+	"github.com/ohmymajo/http-assert/pkg/expr"
+)
+
+func (h HttpJson) Expect(expression string) HttpJson {
+	program, err := expr.Compile(expression, map[string]string{
+		"body":   "dyn",
+		"header": "map",
+	})
+	if err != nil {
+		panic(err.Error())
+	}
+
+	var correct bool
+	if (h.Type == "header" || h.Type == "body") && h.evaluate() {
+		header := http.Header{}
+		if h.Header != nil {
+			header = *h.Header
+		}
+
+		correct, err = program.Eval(map[string]interface{}{
+			"body":   h.Body,
+			"header": header,
+		})
+		if err != nil {
+			return h.result(false, "Expect(%q): %s", expression, err)
+		}
+	}
+
+	return h.result(correct, "Expect(%q)", expression)
+}
@@ -0,0 +1,63 @@
+package assert
+
+import (
+	"net/http"
+	"net/http/httptest"
+)
+
+type HandlerClient struct {
+	handler http.Handler
+}
+
+func Handler(h http.Handler, middleware ...func(http.Handler) http.Handler) HandlerClient {
+	return HandlerClient{handler: h}.Use(middleware...)
+}
+
+func (c HandlerClient) Use(middleware ...func(http.Handler) http.Handler) HandlerClient {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		c.handler = middleware[i](c.handler)
+	}
+
+	return c
+}
+
+func (c HandlerClient) Request(method, path string) *RequestBuilder {
+	r := Request(method, path)
+	r.handler = c.handler
+	return r
+}
+
+func (c HandlerClient) Get(path string) *RequestBuilder {
+	return c.Request(http.MethodGet, path)
+}
+
+func (c HandlerClient) Post(path string) *RequestBuilder {
+	return c.Request(http.MethodPost, path)
+}
+
+func (c HandlerClient) Put(path string) *RequestBuilder {
+	return c.Request(http.MethodPut, path)
+}
+
+func (c HandlerClient) Patch(path string) *RequestBuilder {
+	return c.Request(http.MethodPatch, path)
+}
+
+func (c HandlerClient) Delete(path string) *RequestBuilder {
+	return c.Request(http.MethodDelete, path)
+}
+
+func serve(h http.Handler, req *http.Request) Http {
+	if req.Host == "" {
+		req.Host = "example.com"
+	}
+	req.RequestURI = req.URL.RequestURI()
+	req.RemoteAddr = "192.0.2.1:1234"
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	resp := rec.Result()
+	resp.Request = req
+	return New(resp)
+}
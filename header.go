@@ -0,0 +1,123 @@
+package assert
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ohmymajo/http-assert/pkg/header"
+	"github.com/ohmymajo/http-assert/pkg/validation"
+)
+
+func (h HttpJson) compareText(fieldName, op string, value interface{}) (bool, error) {
+	var raw string
+	if h.Type == "query" {
+		raw, _ = queryValue(h.Query, fieldName)
+	} else {
+		raw = h.Header.Get(fieldName)
+	}
+
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return false, nil
+	}
+
+	if d, ok := value.(time.Duration); ok {
+		hVal, err := parseDuration(raw)
+		if err != nil {
+			return false, fmt.Errorf("cannot parse %s %s value %q as duration", h.Type, fieldName, raw)
+		}
+
+		return validation.CompareValues(int64(hVal), int64(d), op), nil
+	}
+
+	vType := validation.GetValueType(value)
+	if vType != "int" && vType != "float" {
+		return false, fmt.Errorf("cannot compare %s %s with %s value", h.Type, fieldName, vType)
+	}
+
+	var hVal interface{}
+	if i, err := strconv.Atoi(raw); err == nil {
+		hVal = i
+	} else if f, err := strconv.ParseFloat(raw, 64); err == nil {
+		hVal = f
+	} else {
+		return false, fmt.Errorf("cannot parse %s %s value %q as number", h.Type, fieldName, raw)
+	}
+
+	return validation.CompareValues(hVal, value, op), nil
+}
+
+func parseDuration(raw string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(raw); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	return time.ParseDuration(raw)
+}
+
+func (h HttpJson) HeaderValues(fieldName string) []string {
+	if h.Type != "header" || h.Header == nil {
+		return nil
+	}
+
+	return h.Header.Values(fieldName)
+}
+
+func (h HttpJson) HasHeaderValue(fieldName, value string) HttpJson {
+	var correct bool
+	if h.Type == "header" && h.evaluate() {
+		for _, v := range h.Header.Values(fieldName) {
+			if v == value {
+				correct = true
+				break
+			}
+		}
+
+		if !correct {
+			for _, token := range headerTokens(h.Header, fieldName) {
+				if token == value {
+					correct = true
+					break
+				}
+			}
+		}
+	}
+
+	return h.result(correct, "HasHeaderValue(%q, %q)", fieldName, value)
+}
+
+func (h HttpJson) HasHeaderToken(fieldName, token string) HttpJson {
+	var correct bool
+	if h.Type == "header" && h.evaluate() {
+		for _, t := range headerTokens(h.Header, fieldName) {
+			name, _, _ := strings.Cut(t, ";")
+			if strings.EqualFold(t, token) || strings.EqualFold(strings.TrimSpace(name), token) {
+				correct = true
+				break
+			}
+		}
+	}
+
+	return h.result(correct, "HasHeaderToken(%q, %q)", fieldName, token)
+}
+
+func (h HttpJson) HeaderCount(fieldName string, count int) HttpJson {
+	var correct bool
+	if h.Type == "header" && h.evaluate() {
+		correct = len(headerTokens(h.Header, fieldName)) == count
+	}
+
+	return h.result(correct, "HeaderCount(%q, %d)", fieldName, count)
+}
+
+func headerTokens(h *http.Header, fieldName string) []string {
+	values := h.Values(fieldName)
+	if http.CanonicalHeaderKey(fieldName) == "Set-Cookie" {
+		return values
+	}
+
+	return header.SplitList(values)
+}
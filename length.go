@@ -0,0 +1,110 @@
+package assert
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ohmymajo/http-assert/pkg/filter"
+)
+
+func (h HttpJson) HasLengthGte(fieldName string, length int) HttpJson {
+	var correct bool
+	if (h.Type == "header" || h.Type == "query" || h.Type == "body") && h.evaluate() {
+		l, ok, err := h.lengthOf(fieldName)
+		if err != nil {
+			return h.bodyFailure(err, "HasLengthGte(%q, %d)", fieldName, length)
+		}
+		correct = ok && l >= length
+	}
+
+	return h.result(correct, "HasLengthGte(%q, %d)", fieldName, length)
+}
+
+func (h HttpJson) HasLengthLte(fieldName string, length int) HttpJson {
+	var correct bool
+	if (h.Type == "header" || h.Type == "query" || h.Type == "body") && h.evaluate() {
+		l, ok, err := h.lengthOf(fieldName)
+		if err != nil {
+			return h.bodyFailure(err, "HasLengthLte(%q, %d)", fieldName, length)
+		}
+		correct = ok && l <= length
+	}
+
+	return h.result(correct, "HasLengthLte(%q, %d)", fieldName, length)
+}
+
+func (h HttpJson) HasLengthBetween(fieldName string, min, max int) HttpJson {
+	var correct bool
+	if (h.Type == "header" || h.Type == "query" || h.Type == "body") && h.evaluate() {
+		l, ok, err := h.lengthOf(fieldName)
+		if err != nil {
+			return h.bodyFailure(err, "HasLengthBetween(%q, %d, %d)", fieldName, min, max)
+		}
+		correct = ok && l >= min && l <= max
+	}
+
+	return h.result(correct, "HasLengthBetween(%q, %d, %d)", fieldName, min, max)
+}
+
+func (h HttpJson) IsEmpty(fieldName string) HttpJson {
+	var correct bool
+	if (h.Type == "header" || h.Type == "query" || h.Type == "body") && h.evaluate() {
+		l, ok, err := h.lengthOf(fieldName)
+		if err != nil {
+			return h.bodyFailure(err, "IsEmpty(%q)", fieldName)
+		}
+		correct = ok && l == 0
+	}
+
+	return h.result(correct, "IsEmpty(%q)", fieldName)
+}
+
+func (h HttpJson) NotEmpty(fieldName string) HttpJson {
+	var correct bool
+	if (h.Type == "header" || h.Type == "query" || h.Type == "body") && h.evaluate() {
+		l, ok, err := h.lengthOf(fieldName)
+		if err != nil {
+			return h.bodyFailure(err, "NotEmpty(%q)", fieldName)
+		}
+		correct = ok && l > 0
+	}
+
+	return h.result(correct, "NotEmpty(%q)", fieldName)
+}
+
+func (h HttpJson) lengthOf(fieldName string) (int, bool, error) {
+	if h.Type == "header" {
+		return utf8.RuneCountInString(h.Header.Get(fieldName)), len(h.Header.Values(fieldName)) > 0, nil
+	}
+
+	if h.Type == "query" {
+		v, ok := queryValue(h.Query, fieldName)
+		return utf8.RuneCountInString(v), ok, nil
+	}
+
+	if err := h.bodyError(false); err != nil {
+		return 0, false, err
+	}
+
+	if strings.Contains(fieldName, "*") {
+		return len(filter.FindAll(fieldName, h.Body)), true, nil
+	}
+
+	var v interface{}
+	if b, ok := h.Body.(map[string]interface{}); ok && !strings.Contains(fieldName, ".") {
+		v = b[fieldName]
+	} else {
+		v = filter.Find(fieldName, h.Body)
+	}
+
+	switch val := v.(type) {
+	case string:
+		return utf8.RuneCountInString(val), true, nil
+	case []interface{}:
+		return len(val), true, nil
+	case map[string]interface{}:
+		return len(val), true, nil
+	default:
+		return 0, false, nil
+	}
+}
@@ -0,0 +1,316 @@
+package expr
+
+import "fmt"
+
+const (
+	typeDyn    = "dyn"
+	typeNull   = "null"
+	typeBool   = "bool"
+	typeInt    = "int"
+	typeFloat  = "float"
+	typeString = "string"
+	typeList   = "list"
+	typeMap    = "map"
+)
+
+type function struct {
+	receiver []string
+	args     [][]string
+	result   string
+}
+
+var methods = map[string]function{
+	"size":       {receiver: []string{typeString, typeList, typeMap}, result: typeInt},
+	"startsWith": {receiver: []string{typeString}, args: [][]string{{typeString}}, result: typeBool},
+	"endsWith":   {receiver: []string{typeString}, args: [][]string{{typeString}}, result: typeBool},
+	"contains":   {receiver: []string{typeString}, args: [][]string{{typeString}}, result: typeBool},
+	"matches":    {receiver: []string{typeString}, args: [][]string{{typeString}}, result: typeBool},
+	"lower":      {receiver: []string{typeString}, result: typeString},
+	"upper":      {receiver: []string{typeString}, result: typeString},
+}
+
+var functions = map[string]function{
+	"size":   {args: [][]string{{typeString, typeList, typeMap}}, result: typeInt},
+	"int":    {args: [][]string{{typeInt, typeFloat, typeString}}, result: typeInt},
+	"float":  {args: [][]string{{typeInt, typeFloat, typeString}}, result: typeFloat},
+	"string": {args: [][]string{{typeInt, typeFloat, typeString, typeBool}}, result: typeString},
+	"has":    {args: [][]string{{typeDyn}}, result: typeBool},
+}
+
+func check(n node, scope map[string]string) (string, error) {
+	switch n := n.(type) {
+	case literalNode:
+		return typeOf(n.value), nil
+	case identNode:
+		t, ok := scope[n.name]
+		if !ok {
+			return "", fmt.Errorf("undeclared reference %q", n.name)
+		}
+
+		return t, nil
+	case listNode:
+		for _, item := range n.items {
+			if _, err := check(item, scope); err != nil {
+				return "", err
+			}
+		}
+
+		return typeList, nil
+	case selectNode:
+		t, err := check(n.operand, scope)
+		if err != nil {
+			return "", err
+		}
+
+		if t != typeDyn && t != typeMap {
+			return "", fmt.Errorf("cannot select field %q from %s", n.field, t)
+		}
+
+		return typeDyn, nil
+	case indexNode:
+		t, err := check(n.operand, scope)
+		if err != nil {
+			return "", err
+		}
+
+		i, err := check(n.index, scope)
+		if err != nil {
+			return "", err
+		}
+
+		if t == typeList && !oneOf(i, typeInt) {
+			return "", fmt.Errorf("list index must be int, got %s", i)
+		} else if t == typeMap && !oneOf(i, typeString) {
+			return "", fmt.Errorf("map key must be string, got %s", i)
+		} else if t != typeDyn && t != typeList && t != typeMap {
+			return "", fmt.Errorf("cannot index %s", t)
+		}
+
+		return typeDyn, nil
+	case callNode:
+		return checkCall(n, scope)
+	case macroNode:
+		t, err := check(n.target, scope)
+		if err != nil {
+			return "", err
+		}
+
+		if !oneOf(t, typeList, typeMap) {
+			return "", fmt.Errorf("%s() cannot iterate over %s", n.fn, t)
+		}
+
+		inner := map[string]string{}
+		for k, v := range scope {
+			inner[k] = v
+		}
+		inner[n.variable] = typeDyn
+
+		b, err := check(n.body, inner)
+		if err != nil {
+			return "", err
+		}
+
+		if n.fn == "map" {
+			return typeList, nil
+		}
+
+		if !oneOf(b, typeBool) {
+			return "", fmt.Errorf("%s() predicate must be bool, got %s", n.fn, b)
+		}
+
+		if n.fn == "filter" {
+			return typeList, nil
+		}
+
+		return typeBool, nil
+	case unaryNode:
+		t, err := check(n.operand, scope)
+		if err != nil {
+			return "", err
+		}
+
+		if n.op == "!" {
+			if !oneOf(t, typeBool) {
+				return "", fmt.Errorf("operator ! cannot be applied to %s", t)
+			}
+
+			return typeBool, nil
+		}
+
+		if !oneOf(t, typeInt, typeFloat) {
+			return "", fmt.Errorf("operator - cannot be applied to %s", t)
+		}
+
+		return t, nil
+	case binaryNode:
+		return checkBinary(n, scope)
+	case condNode:
+		c, err := check(n.cond, scope)
+		if err != nil {
+			return "", err
+		}
+
+		if !oneOf(c, typeBool) {
+			return "", fmt.Errorf("condition must be bool, got %s", c)
+		}
+
+		a, err := check(n.then, scope)
+		if err != nil {
+			return "", err
+		}
+
+		b, err := check(n.els, scope)
+		if err != nil {
+			return "", err
+		}
+
+		if a == b {
+			return a, nil
+		}
+
+		return typeDyn, nil
+	}
+
+	return "", fmt.Errorf("unknown expression")
+}
+
+func checkCall(n callNode, scope map[string]string) (string, error) {
+	var f function
+	var ok bool
+	if n.target == nil {
+		f, ok = functions[n.fn]
+	} else {
+		f, ok = methods[n.fn]
+	}
+
+	if !ok {
+		return "", fmt.Errorf("undeclared function %q", n.fn)
+	}
+
+	if n.target != nil {
+		t, err := check(n.target, scope)
+		if err != nil {
+			return "", err
+		}
+
+		if !oneOf(t, f.receiver...) {
+			return "", fmt.Errorf("%s() cannot be called on %s", n.fn, t)
+		}
+	}
+
+	if len(n.args) != len(f.args) {
+		return "", fmt.Errorf("%s() expects %d arguments, got %d", n.fn, len(f.args), len(n.args))
+	}
+
+	if n.fn == "has" {
+		if _, ok := n.args[0].(selectNode); !ok {
+			return "", fmt.Errorf("has() expects a field selection")
+		}
+	}
+
+	for i, arg := range n.args {
+		t, err := check(arg, scope)
+		if err != nil {
+			return "", err
+		}
+
+		if !oneOf(t, f.args[i]...) {
+			return "", fmt.Errorf("%s() argument %d cannot be %s", n.fn, i+1, t)
+		}
+	}
+
+	return f.result, nil
+}
+
+func checkBinary(n binaryNode, scope map[string]string) (string, error) {
+	a, err := check(n.left, scope)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := check(n.right, scope)
+	if err != nil {
+		return "", err
+	}
+
+	mismatch := fmt.Errorf("operator %s cannot be applied to %s and %s", n.op, a, b)
+
+	switch n.op {
+	case "&&", "||":
+		if !oneOf(a, typeBool) || !oneOf(b, typeBool) {
+			return "", mismatch
+		}
+
+		return typeBool, nil
+	case "==", "!=":
+		if a != typeDyn && b != typeDyn && a != typeNull && b != typeNull && a != b && !(isNumeric(a) && isNumeric(b)) {
+			return "", mismatch
+		}
+
+		return typeBool, nil
+	case "<", "<=", ">", ">=":
+		if a == typeDyn || b == typeDyn || (isNumeric(a) && isNumeric(b)) || (a == typeString && b == typeString) {
+			return typeBool, nil
+		}
+
+		return "", mismatch
+	case "in":
+		if !oneOf(b, typeList, typeMap) {
+			return "", mismatch
+		}
+
+		return typeBool, nil
+	case "+":
+		if a == typeDyn || b == typeDyn {
+			return typeDyn, nil
+		} else if isNumeric(a) && isNumeric(b) {
+			return numericResult(a, b), nil
+		} else if a == b && (a == typeString || a == typeList) {
+			return a, nil
+		}
+
+		return "", mismatch
+	case "%":
+		if !oneOf(a, typeInt) || !oneOf(b, typeInt) {
+			return "", mismatch
+		}
+
+		return typeInt, nil
+	default:
+		if !oneOf(a, typeInt, typeFloat) || !oneOf(b, typeInt, typeFloat) {
+			return "", mismatch
+		}
+
+		if a == typeDyn || b == typeDyn {
+			return typeDyn, nil
+		}
+
+		return numericResult(a, b), nil
+	}
+}
+
+func oneOf(t string, types ...string) bool {
+	if t == typeDyn {
+		return true
+	}
+
+	for _, v := range types {
+		if v == typeDyn || v == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isNumeric(t string) bool {
+	return t == typeInt || t == typeFloat
+}
+
+func numericResult(a, b string) string {
+	if a == typeInt && b == typeInt {
+		return typeInt
+	}
+
+	return typeFloat
+}
@@ -0,0 +1,588 @@
+package expr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ohmymajo/http-assert/pkg/filter"
+	"github.com/ohmymajo/http-assert/pkg/validation"
+)
+
+var compareOps = map[string]string{
+	"==": "eq",
+	"!=": "ne",
+	"<":  "lt",
+	"<=": "lte",
+	">":  "gt",
+	">=": "gte",
+}
+
+func eval(n node, env map[string]interface{}) (interface{}, error) {
+	switch n := n.(type) {
+	case literalNode:
+		return n.value, nil
+	case identNode:
+		return normalize(env[n.name]), nil
+	case listNode:
+		items := make([]interface{}, 0, len(n.items))
+		for _, item := range n.items {
+			v, err := eval(item, env)
+			if err != nil {
+				return nil, err
+			}
+
+			items = append(items, v)
+		}
+
+		return items, nil
+	case selectNode:
+		if path, ok := bodyPath(n); ok {
+			return normalize(filter.Find(path, env["body"])), nil
+		}
+
+		v, err := eval(n.operand, env)
+		if err != nil {
+			return nil, err
+		}
+
+		return lookup(v, n.field)
+	case indexNode:
+		if path, ok := bodyPath(n); ok {
+			return normalize(filter.Find(path, env["body"])), nil
+		}
+
+		v, err := eval(n.operand, env)
+		if err != nil {
+			return nil, err
+		}
+
+		i, err := eval(n.index, env)
+		if err != nil {
+			return nil, err
+		}
+
+		if arr, ok := v.([]interface{}); ok {
+			ndx, ok := i.(int)
+			if !ok {
+				return nil, fmt.Errorf("list index must be int, got %s", typeOf(i))
+			}
+			if ndx < 0 || ndx >= len(arr) {
+				return nil, fmt.Errorf("index %d out of range", ndx)
+			}
+
+			return normalize(arr[ndx]), nil
+		}
+
+		key, ok := i.(string)
+		if !ok {
+			return nil, fmt.Errorf("map key must be string, got %s", typeOf(i))
+		}
+
+		return lookup(v, key)
+	case callNode:
+		return evalCall(n, env)
+	case macroNode:
+		return evalMacro(n, env)
+	case unaryNode:
+		v, err := eval(n.operand, env)
+		if err != nil {
+			return nil, err
+		}
+
+		switch v := v.(type) {
+		case bool:
+			if n.op == "!" {
+				return !v, nil
+			}
+		case int:
+			if n.op == "-" {
+				return -v, nil
+			}
+		case float64:
+			if n.op == "-" {
+				return -v, nil
+			}
+		}
+
+		return nil, fmt.Errorf("operator %s cannot be applied to %s", n.op, typeOf(v))
+	case binaryNode:
+		return evalBinary(n, env)
+	case condNode:
+		c, err := eval(n.cond, env)
+		if err != nil {
+			return nil, err
+		}
+
+		b, ok := c.(bool)
+		if !ok {
+			return nil, fmt.Errorf("condition must be bool, got %s", typeOf(c))
+		}
+
+		if b {
+			return eval(n.then, env)
+		}
+
+		return eval(n.els, env)
+	}
+
+	return nil, fmt.Errorf("unknown expression")
+}
+
+func evalBinary(n binaryNode, env map[string]interface{}) (interface{}, error) {
+	a, err := eval(n.left, env)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.op == "&&" || n.op == "||" {
+		left, ok := a.(bool)
+		if !ok {
+			return nil, fmt.Errorf("operator %s cannot be applied to %s", n.op, typeOf(a))
+		}
+
+		if (n.op == "&&" && !left) || (n.op == "||" && left) {
+			return left, nil
+		}
+
+		b, err := eval(n.right, env)
+		if err != nil {
+			return nil, err
+		}
+
+		right, ok := b.(bool)
+		if !ok {
+			return nil, fmt.Errorf("operator %s cannot be applied to %s", n.op, typeOf(b))
+		}
+
+		return right, nil
+	}
+
+	b, err := eval(n.right, env)
+	if err != nil {
+		return nil, err
+	}
+
+	mismatch := fmt.Errorf("operator %s cannot be applied to %s and %s", n.op, typeOf(a), typeOf(b))
+
+	switch n.op {
+	case "==":
+		return equal(a, b), nil
+	case "!=":
+		return !equal(a, b), nil
+	case "<", "<=", ">", ">=":
+		ta, tb := typeOf(a), typeOf(b)
+		if (isNumeric(ta) && isNumeric(tb)) || (ta == typeString && tb == typeString) {
+			return validation.CompareValues(a, b, compareOps[n.op]), nil
+		}
+
+		return nil, mismatch
+	case "in":
+		switch c := b.(type) {
+		case []interface{}:
+			for _, item := range c {
+				if equal(a, item) {
+					return true, nil
+				}
+			}
+
+			return false, nil
+		case map[string]interface{}:
+			key, ok := a.(string)
+			if !ok {
+				return nil, mismatch
+			}
+
+			_, found := c[key]
+			return found, nil
+		case http.Header:
+			key, ok := a.(string)
+			if !ok {
+				return nil, mismatch
+			}
+
+			return len(c.Values(key)) > 0, nil
+		}
+
+		return nil, mismatch
+	}
+
+	if sa, ok := a.(string); ok && n.op == "+" {
+		if sb, ok := b.(string); ok {
+			return sa + sb, nil
+		}
+
+		return nil, mismatch
+	}
+
+	if la, ok := a.([]interface{}); ok && n.op == "+" {
+		if lb, ok := b.([]interface{}); ok {
+			return append(append([]interface{}{}, la...), lb...), nil
+		}
+
+		return nil, mismatch
+	}
+
+	ia, aInt := a.(int)
+	ib, bInt := b.(int)
+	if aInt && bInt {
+		switch n.op {
+		case "+":
+			return ia + ib, nil
+		case "-":
+			return ia - ib, nil
+		case "*":
+			return ia * ib, nil
+		case "/", "%":
+			if ib == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+
+			if n.op == "/" {
+				return ia / ib, nil
+			}
+
+			return ia % ib, nil
+		}
+	}
+
+	fa, aOk := toFloat(a)
+	fb, bOk := toFloat(b)
+	if !aOk || !bOk || n.op == "%" {
+		return nil, mismatch
+	}
+
+	switch n.op {
+	case "+":
+		return fa + fb, nil
+	case "-":
+		return fa - fb, nil
+	case "*":
+		return fa * fb, nil
+	case "/":
+		return fa / fb, nil
+	}
+
+	return nil, mismatch
+}
+
+func evalCall(n callNode, env map[string]interface{}) (interface{}, error) {
+	if n.fn == "has" {
+		sel := n.args[0].(selectNode)
+
+		v, err := eval(sel.operand, env)
+		if err != nil {
+			return nil, err
+		}
+
+		switch v := v.(type) {
+		case map[string]interface{}:
+			_, found := v[sel.field]
+			return found, nil
+		case http.Header:
+			return len(v.Values(sel.field)) > 0, nil
+		}
+
+		return false, nil
+	}
+
+	var args []interface{}
+	if n.target != nil {
+		v, err := eval(n.target, env)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, v)
+	}
+
+	for _, arg := range n.args {
+		v, err := eval(arg, env)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, v)
+	}
+
+	switch n.fn {
+	case "size":
+		switch v := args[0].(type) {
+		case string:
+			return utf8.RuneCountInString(v), nil
+		case []interface{}:
+			return len(v), nil
+		case map[string]interface{}:
+			return len(v), nil
+		case http.Header:
+			return len(v), nil
+		}
+	case "startsWith", "endsWith", "contains", "matches":
+		s, ok := args[0].(string)
+		sub, subOk := args[1].(string)
+		if !ok || !subOk {
+			break
+		}
+
+		switch n.fn {
+		case "startsWith":
+			return strings.HasPrefix(s, sub), nil
+		case "endsWith":
+			return strings.HasSuffix(s, sub), nil
+		case "contains":
+			return strings.Contains(s, sub), nil
+		default:
+			re, err := regexp.Compile(sub)
+			if err != nil {
+				return nil, fmt.Errorf("matches(): %s", err)
+			}
+
+			return re.MatchString(s), nil
+		}
+	case "lower", "upper":
+		s, ok := args[0].(string)
+		if !ok {
+			break
+		}
+
+		if n.fn == "lower" {
+			return strings.ToLower(s), nil
+		}
+
+		return strings.ToUpper(s), nil
+	case "int":
+		switch v := args[0].(type) {
+		case int:
+			return v, nil
+		case float64:
+			return int(v), nil
+		case string:
+			i, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("int(): cannot convert %q", v)
+			}
+
+			return i, nil
+		}
+	case "float":
+		switch v := args[0].(type) {
+		case int:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		case string:
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("float(): cannot convert %q", v)
+			}
+
+			return f, nil
+		}
+	case "string":
+		switch v := args[0].(type) {
+		case string, int, float64, bool:
+			return fmt.Sprintf("%v", v), nil
+		}
+	}
+
+	types := make([]string, len(args))
+	for i, arg := range args {
+		types[i] = typeOf(arg)
+	}
+
+	return nil, fmt.Errorf("%s() cannot be applied to %s", n.fn, strings.Join(types, ", "))
+}
+
+func evalMacro(n macroNode, env map[string]interface{}) (interface{}, error) {
+	target, err := eval(n.target, env)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []interface{}
+	switch v := target.(type) {
+	case []interface{}:
+		items = v
+	case map[string]interface{}:
+		for key := range v {
+			items = append(items, key)
+		}
+	default:
+		return nil, fmt.Errorf("%s() cannot iterate over %s", n.fn, typeOf(target))
+	}
+
+	inner := map[string]interface{}{}
+	for k, v := range env {
+		inner[k] = v
+	}
+
+	var matches int
+	var results []interface{}
+	for _, item := range items {
+		inner[n.variable] = normalize(item)
+
+		v, err := eval(n.body, inner)
+		if err != nil {
+			return nil, err
+		}
+
+		if n.fn == "map" {
+			results = append(results, v)
+			continue
+		}
+
+		ok, isBool := v.(bool)
+		if !isBool {
+			return nil, fmt.Errorf("%s() predicate must be bool, got %s", n.fn, typeOf(v))
+		}
+
+		if n.fn == "all" && !ok {
+			return false, nil
+		} else if n.fn == "exists" && ok {
+			return true, nil
+		} else if ok {
+			matches++
+			results = append(results, item)
+		}
+	}
+
+	switch n.fn {
+	case "all":
+		return true, nil
+	case "exists":
+		return false, nil
+	case "exists_one":
+		return matches == 1, nil
+	}
+
+	if results == nil {
+		results = []interface{}{}
+	}
+
+	return results, nil
+}
+
+func bodyPath(n node) (string, bool) {
+	switch n := n.(type) {
+	case identNode:
+		return "", n.name == "body"
+	case selectNode:
+		path, ok := bodyPath(n.operand)
+		return joinPath(path, n.field), ok && !strings.Contains(n.field, ".")
+	case indexNode:
+		path, ok := bodyPath(n.operand)
+		if !ok {
+			return "", false
+		}
+
+		lit, ok := n.index.(literalNode)
+		if !ok {
+			return "", false
+		}
+
+		switch v := lit.value.(type) {
+		case int:
+			return joinPath(path, strconv.Itoa(v)), true
+		case string:
+			return joinPath(path, v), v != "" && !strings.Contains(v, ".")
+		}
+	}
+
+	return "", false
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+
+	return path + "." + field
+}
+
+func lookup(v interface{}, key string) (interface{}, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return normalize(v[key]), nil
+	case http.Header:
+		values := v.Values(key)
+		if len(values) == 0 {
+			return nil, nil
+		}
+
+		return strings.Join(values, ", "), nil
+	case nil:
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("cannot select field %q from %s", key, typeOf(v))
+}
+
+func equal(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	ta, tb := typeOf(a), typeOf(b)
+	if (isNumeric(ta) && isNumeric(tb)) || (ta == tb && (ta == typeString || ta == typeBool)) {
+		return validation.CompareValues(a, b, "eq")
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+
+	return 0, false
+}
+
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		num, _ := validation.NumericValue(v)
+		return num
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = normalize(item)
+		}
+
+		return items
+	case int8, int16, int32, int64, float32:
+		num, _ := validation.NumericValue(v)
+		return num
+	}
+
+	return v
+}
+
+func typeOf(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return typeNull
+	case bool:
+		return typeBool
+	case int:
+		return typeInt
+	case float64:
+		return typeFloat
+	case string:
+		return typeString
+	case []interface{}:
+		return typeList
+	case map[string]interface{}, http.Header:
+		return typeMap
+	}
+
+	return typeDyn
+}
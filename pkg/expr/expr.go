@@ -0,0 +1,45 @@
+package expr
+
+import "fmt"
+
+type Program struct {
+	Source string
+	root   node
+}
+
+func Compile(src string, declarations map[string]string) (*Program, error) {
+	root, err := parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("parse %q: %s", src, err)
+	}
+
+	scope := map[string]string{}
+	for name, t := range declarations {
+		scope[name] = t
+	}
+
+	t, err := check(root, scope)
+	if err != nil {
+		return nil, fmt.Errorf("check %q: %s", src, err)
+	}
+
+	if !oneOf(t, typeBool) {
+		return nil, fmt.Errorf("check %q: expression must be bool, got %s", src, t)
+	}
+
+	return &Program{Source: src, root: root}, nil
+}
+
+func (p *Program) Eval(vars map[string]interface{}) (bool, error) {
+	v, err := eval(p.root, vars)
+	if err != nil {
+		return false, fmt.Errorf("eval %q: %s", p.Source, err)
+	}
+
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("eval %q: expression must be bool, got %s", p.Source, typeOf(v))
+	}
+
+	return b, nil
+}
@@ -0,0 +1,157 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokInt
+	tokFloat
+	tokString
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+var operators = []string{
+	"==", "!=", "<=", ">=", "&&", "||",
+	"+", "-", "*", "/", "%", "<", ">", "!", ".", ",", "(", ")", "[", "]", "?", ":",
+}
+
+func lex(src string) ([]token, error) {
+	var tokens []token
+
+	i := 0
+	for i < len(src) {
+		c := src[i]
+
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			i++
+			continue
+		}
+
+		if isLetter(c) {
+			j := i
+			for j < len(src) && (isLetter(src[j]) || isDigit(src[j])) {
+				j++
+			}
+
+			tokens = append(tokens, token{kind: tokIdent, text: src[i:j], pos: i})
+			i = j
+			continue
+		}
+
+		if isDigit(c) {
+			j := i
+			kind := tokInt
+			for j < len(src) && isDigit(src[j]) {
+				j++
+			}
+			if j+1 < len(src) && src[j] == '.' && isDigit(src[j+1]) {
+				kind = tokFloat
+				j++
+				for j < len(src) && isDigit(src[j]) {
+					j++
+				}
+			}
+			if j < len(src) && (src[j] == 'e' || src[j] == 'E') {
+				k := j + 1
+				if k < len(src) && (src[k] == '+' || src[k] == '-') {
+					k++
+				}
+				if k < len(src) && isDigit(src[k]) {
+					kind = tokFloat
+					j = k
+					for j < len(src) && isDigit(src[j]) {
+						j++
+					}
+				}
+			}
+
+			tokens = append(tokens, token{kind: kind, text: src[i:j], pos: i})
+			i = j
+			continue
+		}
+
+		if c == '"' || c == '\'' {
+			s, n, err := lexString(src[i:])
+			if err != nil {
+				return nil, fmt.Errorf("%s at position %d", err, i)
+			}
+
+			tokens = append(tokens, token{kind: tokString, text: s, pos: i})
+			i += n
+			continue
+		}
+
+		matched := false
+		for _, op := range operators {
+			if strings.HasPrefix(src[i:], op) {
+				tokens = append(tokens, token{kind: tokOp, text: op, pos: i})
+				i += len(op)
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+		}
+	}
+
+	return append(tokens, token{kind: tokEOF, pos: len(src)}), nil
+}
+
+func lexString(src string) (string, int, error) {
+	quote := src[0]
+
+	var b strings.Builder
+	for i := 1; i < len(src); i++ {
+		c := src[i]
+		if c == quote {
+			return b.String(), i + 1, nil
+		}
+
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+
+		i++
+		if i >= len(src) {
+			break
+		}
+
+		switch src[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case '\\', '"', '\'':
+			b.WriteByte(src[i])
+		default:
+			return "", 0, fmt.Errorf("unknown escape sequence \\%c", src[i])
+		}
+	}
+
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+func isLetter(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,381 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type node interface{}
+
+type literalNode struct {
+	value interface{}
+}
+
+type identNode struct {
+	name string
+}
+
+type selectNode struct {
+	operand node
+	field   string
+}
+
+type indexNode struct {
+	operand node
+	index   node
+}
+
+type callNode struct {
+	target node
+	fn     string
+	args   []node
+}
+
+type macroNode struct {
+	target   node
+	fn       string
+	variable string
+	body     node
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+type binaryNode struct {
+	op    string
+	left  node
+	right node
+}
+
+type condNode struct {
+	cond node
+	then node
+	els  node
+}
+
+type listNode struct {
+	items []node
+}
+
+var macros = map[string]bool{
+	"all":        true,
+	"exists":     true,
+	"exists_one": true,
+	"filter":     true,
+	"map":        true,
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func parse(src string) (node, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	n, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+	}
+
+	return n, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *parser) accept(op string) bool {
+	t := p.peek()
+	if t.kind == tokOp && t.text == op {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if !p.accept(op) {
+		t := p.peek()
+		if t.kind == tokEOF {
+			return fmt.Errorf("expected %q at end of expression", op)
+		}
+
+		return fmt.Errorf("expected %q at position %d, got %q", op, t.pos, t.text)
+	}
+
+	return nil
+}
+
+func (p *parser) expr() (node, error) {
+	cond, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.accept("?") {
+		return cond, nil
+	}
+
+	then, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+
+	els, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+
+	return condNode{cond: cond, then: then, els: els}, nil
+}
+
+func (p *parser) or() (node, error) {
+	return p.binary(p.and, "||")
+}
+
+func (p *parser) and() (node, error) {
+	return p.binary(p.relation, "&&")
+}
+
+func (p *parser) relation() (node, error) {
+	return p.binary(p.addition, "==", "!=", "<", "<=", ">", ">=", "in")
+}
+
+func (p *parser) addition() (node, error) {
+	return p.binary(p.multiplication, "+", "-")
+}
+
+func (p *parser) multiplication() (node, error) {
+	return p.binary(p.unary, "*", "/", "%")
+}
+
+func (p *parser) binary(operand func() (node, error), ops ...string) (node, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+
+		matched := ""
+		for _, op := range ops {
+			if (t.kind == tokOp || t.kind == tokIdent) && t.text == op {
+				matched = op
+				break
+			}
+		}
+
+		if matched == "" {
+			return left, nil
+		}
+
+		p.next()
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+
+		left = binaryNode{op: matched, left: left, right: right}
+	}
+}
+
+func (p *parser) unary() (node, error) {
+	if p.accept("!") {
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		return unaryNode{op: "!", operand: operand}, nil
+	}
+
+	if p.accept("-") {
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		return unaryNode{op: "-", operand: operand}, nil
+	}
+
+	return p.postfix()
+}
+
+func (p *parser) postfix() (node, error) {
+	n, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if p.accept(".") {
+			t := p.next()
+			if t.kind != tokIdent {
+				return nil, fmt.Errorf("expected field name at position %d", t.pos)
+			}
+
+			if !p.accept("(") {
+				n = selectNode{operand: n, field: t.text}
+				continue
+			}
+
+			args, err := p.args(")")
+			if err != nil {
+				return nil, err
+			}
+
+			n, err = p.call(n, t.text, args)
+			if err != nil {
+				return nil, err
+			}
+		} else if p.accept("[") {
+			index, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+
+			n = indexNode{operand: n, index: index}
+		} else {
+			return n, nil
+		}
+	}
+}
+
+func (p *parser) call(target node, fn string, args []node) (node, error) {
+	if !macros[fn] {
+		return callNode{target: target, fn: fn, args: args}, nil
+	}
+
+	if len(args) != 2 {
+		return nil, fmt.Errorf("%s() expects 2 arguments, got %d", fn, len(args))
+	}
+
+	variable, ok := args[0].(identNode)
+	if !ok {
+		return nil, fmt.Errorf("%s() expects a variable name as first argument", fn)
+	}
+
+	return macroNode{target: target, fn: fn, variable: variable.name, body: args[1]}, nil
+}
+
+func (p *parser) args(end string) ([]node, error) {
+	var args []node
+	if p.accept(end) {
+		return args, nil
+	}
+
+	for {
+		arg, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, arg)
+		if p.accept(end) {
+			return args, nil
+		}
+
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) primary() (node, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokInt:
+		v, err := strconv.Atoi(t.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q at position %d", t.text, t.pos)
+		}
+
+		return literalNode{value: v}, nil
+	case tokFloat:
+		v, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", t.text, t.pos)
+		}
+
+		return literalNode{value: v}, nil
+	case tokString:
+		return literalNode{value: t.text}, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return literalNode{value: true}, nil
+		case "false":
+			return literalNode{value: false}, nil
+		case "null":
+			return literalNode{value: nil}, nil
+		}
+
+		if p.accept("(") {
+			args, err := p.args(")")
+			if err != nil {
+				return nil, err
+			}
+
+			return callNode{fn: t.text, args: args}, nil
+		}
+
+		return identNode{name: t.text}, nil
+	case tokOp:
+		if t.text == "(" {
+			n, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+
+			return n, nil
+		}
+
+		if t.text == "[" {
+			items, err := p.args("]")
+			if err != nil {
+				return nil, err
+			}
+
+			return listNode{items: items}, nil
+		}
+	case tokEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+}
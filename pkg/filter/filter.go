@@ -8,36 +8,28 @@ import (
 )
 
 func Find(cursor string, data interface{}) interface{} {
-	var value interface{}
-	var lf string
-
 	fields := strings.Split(cursor, ".")
 
 	d := data
-	for idx, field := range fields {
+	for _, field := range fields {
 		numeric, ndx := isInt(field)
 
-		if !numeric {
-			dtmp := filter(field, d)
-			t := validation.GetBodyType(dtmp)
-
-			if t != "object" {
-				value = dtmp
-			} else {
-				d = dtmp
+		if arr, ok := d.([]interface{}); ok && numeric {
+			if ndx < 0 || ndx >= len(arr) {
+				return nil
 			}
+
+			d = arr[ndx]
 		} else {
-			tmp := d.(map[string]interface{})[lf]
-			d = tmp.([]interface{})[ndx]
-			if idx == len(fields)-1 {
-				value = tmp.([]interface{})[ndx]
-			}
+			d = filter(field, d)
 		}
 
-		lf = field
+		if d == nil {
+			return nil
+		}
 	}
 
-	return value
+	return d
 }
 
 func isInt(field string) (bool, int) {
@@ -62,3 +54,46 @@ func filter(field string, data interface{}) interface{} {
 
 	return nil
 }
+
+func FindAll(cursor string, data interface{}) []interface{} {
+	values := []interface{}{data}
+
+	fields := strings.Split(cursor, ".")
+	for _, field := range fields {
+		var next []interface{}
+
+		for _, d := range values {
+			if field == "*" {
+				switch v := d.(type) {
+				case []interface{}:
+					next = append(next, v...)
+				case map[string]interface{}:
+					for _, val := range v {
+						next = append(next, val)
+					}
+				}
+
+				continue
+			}
+
+			numeric, ndx := isInt(field)
+			if arr, ok := d.([]interface{}); ok && numeric {
+				if ndx >= 0 && ndx < len(arr) {
+					next = append(next, arr[ndx])
+				}
+
+				continue
+			}
+
+			if obj, ok := d.(map[string]interface{}); ok {
+				if val, ok := obj[field]; ok {
+					next = append(next, val)
+				}
+			}
+		}
+
+		values = next
+	}
+
+	return values
+}
@@ -0,0 +1,149 @@
+package header
+
+import (
+	"mime"
+	"strings"
+)
+
+func SplitList(values []string) []string {
+	var tokens []string
+	for _, value := range values {
+		var quoted bool
+		var depth int
+
+		start := 0
+		for i := 0; i < len(value); i++ {
+			switch value[i] {
+			case '\\':
+				if quoted {
+					i++
+				}
+			case '"':
+				quoted = !quoted
+			case '<':
+				if !quoted {
+					depth++
+				}
+			case '>':
+				if !quoted && depth > 0 {
+					depth--
+				}
+			case ',':
+				if !quoted && depth == 0 {
+					tokens = appendToken(tokens, value[start:i])
+					start = i + 1
+				}
+			}
+		}
+
+		tokens = appendToken(tokens, value[start:])
+	}
+
+	return tokens
+}
+
+func appendToken(tokens []string, token string) []string {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return tokens
+	}
+
+	return append(tokens, token)
+}
+
+func MatchMediaType(actual, expected string) (bool, error) {
+	expType, expParams, err := mime.ParseMediaType(expected)
+	if err != nil {
+		return false, err
+	}
+
+	actType, actParams, err := mime.ParseMediaType(actual)
+	if err != nil {
+		return false, nil
+	}
+
+	expMain, expSub, _ := strings.Cut(expType, "/")
+	actMain, actSub, _ := strings.Cut(actType, "/")
+	_, actSuffix, hasSuffix := strings.Cut(actSub, "+")
+
+	if expMain != "*" && expMain != actMain {
+		return false, nil
+	}
+
+	if expSub != "*" && expSub != actSub && (!hasSuffix || expSub != "*+"+actSuffix) {
+		return false, nil
+	}
+
+	for name, value := range expParams {
+		v, ok := actParams[name]
+		if !ok || !strings.EqualFold(v, value) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func IsJSONMediaType(contentType string) bool {
+	for _, expected := range []string{"*/json", "*/*+json"} {
+		if ok, _ := MatchMediaType(contentType, expected); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func ParseDirectives(values []string) map[string]string {
+	directives := map[string]string{}
+	for _, token := range SplitList(values) {
+		name, value, _ := strings.Cut(token, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+
+		if _, ok := directives[name]; !ok {
+			directives[name] = value
+		}
+	}
+
+	return directives
+}
+
+func ParseParameters(value string) map[string]string {
+	params := map[string]string{}
+	for _, part := range strings.Split(value, ";") {
+		name, v, _ := strings.Cut(part, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		if _, ok := params[name]; !ok {
+			params[name] = strings.Trim(strings.TrimSpace(v), `"`)
+		}
+	}
+
+	return params
+}
+
+func ParseCSP(values []string) []map[string][]string {
+	var policies []map[string][]string
+	for _, value := range SplitList(values) {
+		policy := map[string][]string{}
+		for _, directive := range strings.Split(value, ";") {
+			fields := strings.Fields(directive)
+			if len(fields) == 0 {
+				continue
+			}
+
+			name := strings.ToLower(fields[0])
+			if _, ok := policy[name]; !ok {
+				policy[name] = fields[1:]
+			}
+		}
+
+		policies = append(policies, policy)
+	}
+
+	return policies
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"time"
 )
 
 func GetBodyType(b interface{}) string {
@@ -126,3 +127,80 @@ func EqualValueWithOP(a, b interface{}, op, aType string) bool {
 
 	return false
 }
+
+func NumericValue(v interface{}) (interface{}, string) {
+	if v == nil {
+		return nil, ""
+	}
+
+	t := GetValueType(v)
+	if t == "int" {
+		val, err := strconv.Atoi(fmt.Sprintf("%v", v))
+		if err == nil {
+			return val, "int"
+		}
+	} else if t == "float" {
+		val, err := strconv.ParseFloat(fmt.Sprintf("%v", v), 64)
+		if err == nil {
+			return val, "float"
+		}
+	}
+
+	return nil, ""
+}
+
+func CompareValues(a, b interface{}, op string) bool {
+	numA, typeA := NumericValue(a)
+	numB, typeB := NumericValue(b)
+	if typeA != "" && typeB != "" {
+		if typeA == "int" && typeB == "float" {
+			numA, typeA = float64(numA.(int)), "float"
+		}
+
+		if op == "eq" {
+			return EqualValue(numA, numB, typeA)
+		} else if op == "ne" {
+			return NotEqualValue(numA, numB, typeA)
+		}
+
+		return EqualValueWithOP(numA, numB, op, typeA)
+	}
+
+	strA, okA := a.(string)
+	strB, okB := b.(string)
+	if okA && okB {
+		timeA, errA := time.Parse(time.RFC3339, strA)
+		timeB, errB := time.Parse(time.RFC3339, strB)
+		if errA == nil && errB == nil {
+			return CompareValues(timeA.UnixNano(), timeB.UnixNano(), op)
+		}
+
+		if op == "eq" {
+			return EqualValue(strA, strB, "string")
+		} else if op == "ne" {
+			return NotEqualValue(strA, strB, "string")
+		} else if op == "gt" {
+			return strA > strB
+		} else if op == "gte" {
+			return strA >= strB
+		} else if op == "lt" {
+			return strA < strB
+		} else if op == "lte" {
+			return strA <= strB
+		}
+
+		return false
+	}
+
+	boolA, okA := a.(bool)
+	boolB, okB := b.(bool)
+	if okA && okB {
+		if op == "eq" {
+			return EqualValue(boolA, boolB, "bool")
+		} else if op == "ne" {
+			return NotEqualValue(boolA, boolB, "bool")
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,115 @@
+package assert
+
+import (
+	"fmt"
+
+	"github.com/ohmymajo/http-assert/pkg/header"
+	"github.com/ohmymajo/http-assert/pkg/validation"
+)
+
+func (h Http) AssertProblem() HttpJson {
+	ct := h.Resp.Header.Get("Content-Type")
+	if ok, _ := header.MatchMediaType(ct, "application/problem+json"); !ok {
+		hj := HttpJson{
+			Type:          "body",
+			Header:        &h.Resp.Header,
+			AssertCorrect: true,
+			Resp:          h.Resp,
+		}
+
+		return hj.ruleResult([]string{fmt.Sprintf("Content-Type must be application/problem+json, got %q", ct)})
+	}
+
+	hj := h.AssertBody()
+	if validation.GetBodyType(hj.Body) != "object" {
+		return hj.ruleResult([]string{"problem details must be a JSON object"})
+	}
+
+	return hj.ruleResult(problemRules(hj.Body.(map[string]interface{}), h.Resp.StatusCode))
+}
+
+func (h HttpJson) WithType(uri string) HttpJson {
+	var correct bool
+	if h.Type == "body" && h.evaluate() {
+		correct = problemMember(h.Body, "type", "about:blank") == uri
+	}
+
+	return h.result(correct, "WithType(%q)", uri)
+}
+
+func (h HttpJson) WithTitle(title string) HttpJson {
+	var correct bool
+	if h.Type == "body" && h.evaluate() {
+		correct = problemMember(h.Body, "title", "") == title
+	}
+
+	return h.result(correct, "WithTitle(%q)", title)
+}
+
+func (h HttpJson) WithStatus(status int) HttpJson {
+	var correct bool
+	if h.Type == "body" && h.evaluate() {
+		if b, ok := h.Body.(map[string]interface{}); ok && b["status"] != nil {
+			correct = validation.CompareValues(b["status"], status, "eq")
+		}
+	}
+
+	return h.result(correct, "WithStatus(%d)", status)
+}
+
+func (h HttpJson) WithDetail(detail string) HttpJson {
+	var correct bool
+	if h.Type == "body" && h.evaluate() {
+		correct = problemMember(h.Body, "detail", "") == detail
+	}
+
+	return h.result(correct, "WithDetail(%q)", detail)
+}
+
+func (h HttpJson) WithInstance(instance string) HttpJson {
+	var correct bool
+	if h.Type == "body" && h.evaluate() {
+		correct = problemMember(h.Body, "instance", "") == instance
+	}
+
+	return h.result(correct, "WithInstance(%q)", instance)
+}
+
+func problemRules(b map[string]interface{}, statusCode int) []string {
+	var failures []string
+
+	for _, member := range []string{"type", "title", "detail", "instance"} {
+		if v, ok := b[member]; ok {
+			if _, isString := v.(string); !isString {
+				failures = append(failures, fmt.Sprintf("problem member %q must be a string", member))
+			}
+		} else if member == "title" {
+			failures = append(failures, fmt.Sprintf("problem member %q is missing", member))
+		}
+	}
+
+	if v, ok := b["status"]; !ok {
+		failures = append(failures, `problem member "status" is missing`)
+	} else if v == nil || validation.GetValueType(v) != "int" {
+		failures = append(failures, `problem member "status" must be an integer`)
+	} else if !validation.CompareValues(v, statusCode, "eq") {
+		failures = append(failures, fmt.Sprintf(`problem member "status" is %v, response status is %d`, v, statusCode))
+	}
+
+	return failures
+}
+
+func problemMember(body interface{}, member, fallback string) string {
+	b, ok := body.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	v, ok := b[member]
+	if !ok {
+		return fallback
+	}
+
+	s, _ := v.(string)
+	return s
+}
@@ -0,0 +1,275 @@
+package assert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+type RequestBuilder struct {
+	Method string
+	URL    string
+
+	params      map[string]string
+	query       url.Values
+	header      http.Header
+	body        []byte
+	contentType string
+	jsonBody    bool
+	fields      map[string]string
+	files       []multipartFile
+	ctx         context.Context
+	timeout     time.Duration
+	client      *http.Client
+	handler     http.Handler
+	vars        *Vars
+}
+
+type multipartFile struct {
+	field    string
+	filename string
+	content  []byte
+}
+
+func Request(method, rawURL string) *RequestBuilder {
+	return &RequestBuilder{
+		Method: method,
+		URL:    rawURL,
+		params: map[string]string{},
+		query:  url.Values{},
+		header: http.Header{},
+		ctx:    context.Background(),
+		client: http.DefaultClient,
+	}
+}
+
+func Get(rawURL string) *RequestBuilder {
+	return Request(http.MethodGet, rawURL)
+}
+
+func Post(rawURL string) *RequestBuilder {
+	return Request(http.MethodPost, rawURL)
+}
+
+func Put(rawURL string) *RequestBuilder {
+	return Request(http.MethodPut, rawURL)
+}
+
+func Patch(rawURL string) *RequestBuilder {
+	return Request(http.MethodPatch, rawURL)
+}
+
+func Delete(rawURL string) *RequestBuilder {
+	return Request(http.MethodDelete, rawURL)
+}
+
+func (r *RequestBuilder) PathParam(name, value string) *RequestBuilder {
+	r.params[name] = value
+	return r
+}
+
+func (r *RequestBuilder) Query(key, value string) *RequestBuilder {
+	r.query.Add(key, value)
+	return r
+}
+
+func (r *RequestBuilder) Header(key, value string) *RequestBuilder {
+	r.header.Add(key, value)
+	return r
+}
+
+func (r *RequestBuilder) BasicAuth(username, password string) *RequestBuilder {
+	req := http.Request{Header: http.Header{}}
+	req.SetBasicAuth(username, password)
+
+	r.header.Set("Authorization", req.Header.Get("Authorization"))
+	return r
+}
+
+func (r *RequestBuilder) BearerToken(token string) *RequestBuilder {
+	r.header.Set("Authorization", "Bearer "+token)
+	return r
+}
+
+func (r *RequestBuilder) Body(contentType string, body []byte) *RequestBuilder {
+	r.contentType = contentType
+	r.body = body
+	r.jsonBody = false
+	return r
+}
+
+func (r *RequestBuilder) JSON(v interface{}) *RequestBuilder {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic("cannot encode json data")
+	}
+
+	r.Body("application/json", b)
+	r.jsonBody = true
+	return r
+}
+
+func (r *RequestBuilder) Form(values url.Values) *RequestBuilder {
+	return r.Body("application/x-www-form-urlencoded", []byte(values.Encode()))
+}
+
+func (r *RequestBuilder) Field(name, value string) *RequestBuilder {
+	if r.fields == nil {
+		r.fields = map[string]string{}
+	}
+
+	r.fields[name] = value
+	return r
+}
+
+func (r *RequestBuilder) File(field, filename string, content []byte) *RequestBuilder {
+	r.files = append(r.files, multipartFile{field: field, filename: filename, content: content})
+	return r
+}
+
+func (r *RequestBuilder) Context(ctx context.Context) *RequestBuilder {
+	r.ctx = ctx
+	return r
+}
+
+func (r *RequestBuilder) Timeout(d time.Duration) *RequestBuilder {
+	r.timeout = d
+	return r
+}
+
+func (r *RequestBuilder) Client(client *http.Client) *RequestBuilder {
+	r.client = client
+	return r
+}
+
+func (r *RequestBuilder) Vars(vars *Vars) *RequestBuilder {
+	r.vars = vars
+	return r
+}
+
+func (r *RequestBuilder) Build() *http.Request {
+	rawURL := r.vars.Interpolate(r.URL)
+	for name, value := range r.params {
+		rawURL = strings.ReplaceAll(rawURL, "{"+name+"}", url.PathEscape(r.vars.Interpolate(value)))
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		panic(fmt.Sprintf("cannot parse url %q", r.URL))
+	}
+
+	q := u.Query()
+	for key, values := range r.query {
+		for _, v := range values {
+			q.Add(key, r.vars.Interpolate(v))
+		}
+	}
+	u.RawQuery = q.Encode()
+
+	body, contentType := r.body, r.contentType
+	if len(r.fields) > 0 || len(r.files) > 0 {
+		body, contentType = r.multipart()
+	} else if r.jsonBody {
+		body = r.vars.interpolateJSON(body)
+	} else if r.vars != nil {
+		body = []byte(r.vars.Interpolate(string(body)))
+	}
+
+	req, err := http.NewRequestWithContext(r.ctx, r.Method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		panic(fmt.Sprintf("cannot build request: %s", err))
+	}
+
+	req.Header = r.header.Clone()
+	for _, values := range req.Header {
+		for i, v := range values {
+			values[i] = r.vars.Interpolate(v)
+		}
+	}
+	if contentType != "" && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	return req
+}
+
+func (r *RequestBuilder) Do() Http {
+	ctx := r.ctx
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	req := r.Build().WithContext(ctx)
+	if r.handler != nil {
+		return serve(r.handler, req)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		panic(fmt.Sprintf("cannot send request %s %s: %s", req.Method, req.URL, err))
+	}
+
+	readBody(resp)
+	return New(resp)
+}
+
+func (r *RequestBuilder) multipart() ([]byte, string) {
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+
+	names := make([]string, 0, len(r.fields))
+	for name := range r.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := w.WriteField(name, r.vars.Interpolate(r.fields[name])); err != nil {
+			panic("cannot write multipart field " + name)
+		}
+	}
+
+	for _, f := range r.files {
+		part, err := w.CreateFormFile(f.field, f.filename)
+		if err == nil {
+			_, err = part.Write(f.content)
+		}
+		if err != nil {
+			panic("cannot write multipart file " + f.filename)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		panic("cannot write multipart body")
+	}
+
+	return b.Bytes(), w.FormDataContentType()
+}
+
+func requestSummary(req *http.Request) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "request: %s %s", req.Method, req.URL)
+
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			content, _ := io.ReadAll(body)
+			body.Close()
+
+			if len(content) > 0 {
+				fmt.Fprintf(&b, "\n  body: %s", snippet(content))
+			}
+		}
+	}
+
+	return b.String()
+}
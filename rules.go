@@ -0,0 +1,513 @@
+package assert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type StubRule struct {
+	Method  string
+	Path    string
+	Query   map[string]string
+	Headers map[string]string
+	Match   func(HttpJson) HttpJson
+
+	Status         int
+	StatusTemplate string
+	ReplyHeaders   map[string]string
+	Body           string
+	DelayFor       time.Duration
+	FaultKind      string
+}
+
+type stubRuleFile struct {
+	Method  string                 `json:"method" yaml:"method"`
+	Path    string                 `json:"path" yaml:"path"`
+	Query   map[string]string      `json:"query" yaml:"query"`
+	Headers map[string]string      `json:"headers" yaml:"headers"`
+	Body    map[string]interface{} `json:"body" yaml:"body"`
+	Reply   struct {
+		Status  interface{}       `json:"status" yaml:"status"`
+		Headers map[string]string `json:"headers" yaml:"headers"`
+		Body    string            `json:"body" yaml:"body"`
+		JSON    interface{}       `json:"json" yaml:"json"`
+		Delay   string            `json:"delay" yaml:"delay"`
+		Fault   string            `json:"fault" yaml:"fault"`
+	} `json:"reply" yaml:"reply"`
+}
+
+type stubRequest struct {
+	Method string
+	Path   string
+	Params map[string]string
+	Query  map[string]string
+	Header map[string]string
+	Body   interface{}
+}
+
+func (s *StubServer) On(method, path string) *StubRule {
+	rule := newStubRule(method, path)
+
+	s.mu.Lock()
+	s.rules = append(s.rules, rule)
+	s.mu.Unlock()
+
+	return rule
+}
+
+func newStubRule(method, path string) *StubRule {
+	return &StubRule{
+		Method:       method,
+		Path:         path,
+		Query:        map[string]string{},
+		Headers:      map[string]string{},
+		Status:       http.StatusOK,
+		ReplyHeaders: map[string]string{},
+	}
+}
+
+func (s *StubServer) LoadRules(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var files []stubRuleFile
+	if isYAML(path) {
+		err = yaml.Unmarshal(b, &files)
+	} else {
+		err = json.Unmarshal(b, &files)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot parse stub rules %s: %s", path, err)
+	}
+
+	var rules []*StubRule
+	for i, f := range files {
+		rule := newStubRule(f.Method, f.Path)
+		rules = append(rules, rule)
+		for k, v := range f.Query {
+			rule.Query[k] = v
+		}
+		for k, v := range f.Headers {
+			rule.Headers[k] = v
+		}
+
+		if len(f.Body) > 0 {
+			body := f.Body
+			rule.Match = func(h HttpJson) HttpJson {
+				for field, value := range body {
+					h = h.Where(field, value)
+				}
+
+				return h
+			}
+		}
+
+		switch status := f.Reply.Status.(type) {
+		case nil:
+		case int:
+			rule.Status = status
+		case float64:
+			rule.Status = int(status)
+		case string:
+			rule.StatusTemplate = status
+		default:
+			return fmt.Errorf("cannot parse stub rule %d status %v", i, status)
+		}
+		for k, v := range f.Reply.Headers {
+			rule.ReplyHeaders[k] = v
+		}
+
+		rule.Body = f.Reply.Body
+		if f.Reply.JSON != nil {
+			rule.replyJSON(f.Reply.JSON)
+		}
+
+		if f.Reply.Delay != "" {
+			rule.DelayFor, err = time.ParseDuration(f.Reply.Delay)
+			if err != nil {
+				return fmt.Errorf("cannot parse stub rule %d delay %q", i, f.Reply.Delay)
+			}
+		}
+
+		if f.Reply.Fault != "" && !faults[f.Reply.Fault] {
+			return fmt.Errorf("cannot parse stub rule %d: unknown fault %q", i, f.Reply.Fault)
+		}
+
+		rule.FaultKind = f.Reply.Fault
+
+		if err := rule.checkTemplates(); err != nil {
+			return fmt.Errorf("cannot parse stub rule %d: %s", i, err)
+		}
+	}
+
+	s.mu.Lock()
+	s.rules = append(s.rules, rules...)
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (r *StubRule) WithQuery(key, value string) *StubRule {
+	r.Query[key] = value
+	return r
+}
+
+func (r *StubRule) WithHeader(key, value string) *StubRule {
+	r.Headers[key] = value
+	return r
+}
+
+func (r *StubRule) WithBody(match func(HttpJson) HttpJson) *StubRule {
+	r.Match = match
+	return r
+}
+
+func (r *StubRule) Reply(status int) *StubRule {
+	r.Status = status
+	return r
+}
+
+func (r *StubRule) ReplyStatus(template string) *StubRule {
+	mustCheckTemplate(template)
+
+	r.StatusTemplate = template
+	return r
+}
+
+func (r *StubRule) ReplyHeader(key, value string) *StubRule {
+	mustCheckTemplate(value)
+
+	r.ReplyHeaders[key] = value
+	return r
+}
+
+func (r *StubRule) ReplyBody(body string) *StubRule {
+	mustCheckTemplate(body)
+
+	r.Body = body
+	return r
+}
+
+func (r *StubRule) ReplyJSON(v interface{}) *StubRule {
+	r.replyJSON(v)
+	mustCheckTemplate(r.Body)
+
+	return r
+}
+
+func (r *StubRule) replyJSON(v interface{}) {
+	b, err := json.Marshal(normalizeYAML(v))
+	if err != nil {
+		panic("cannot encode json data")
+	}
+
+	if _, ok := r.ReplyHeaders["Content-Type"]; !ok {
+		r.ReplyHeaders["Content-Type"] = "application/json"
+	}
+
+	r.Body = string(b)
+}
+
+func (r *StubRule) Delay(d time.Duration) *StubRule {
+	r.DelayFor = d
+	return r
+}
+
+func (r *StubRule) Fault(kind string) *StubRule {
+	if !faults[kind] {
+		panic(fmt.Sprintf("unknown stub fault %q", kind))
+	}
+
+	r.FaultKind = kind
+	return r
+}
+
+func (r *StubRule) match(req RecordedRequest) (map[string]string, bool) {
+	if r.Method != "" && !strings.EqualFold(r.Method, req.Method) {
+		return nil, false
+	}
+
+	params, ok := matchPath(r.Path, req.Path)
+	if !ok {
+		return nil, false
+	}
+
+	for key, value := range r.Query {
+		if req.Query.Get(key) != value {
+			return nil, false
+		}
+	}
+
+	for key, value := range r.Headers {
+		if req.Header.Get(key) != value {
+			return nil, false
+		}
+	}
+
+	if r.Match != nil && !matchBody(r.Match, req) {
+		return nil, false
+	}
+
+	return params, true
+}
+
+func matchBody(match func(HttpJson) HttpJson, req RecordedRequest) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+
+	return match(req.AssertBody()).Check()
+}
+
+func (r *StubRule) reply(w http.ResponseWriter, req RecordedRequest, params map[string]string) {
+	if r.DelayFor > 0 {
+		time.Sleep(r.DelayFor)
+	}
+
+	if r.FaultKind != "" {
+		fault(w, r.FaultKind)
+		return
+	}
+
+	data := stubRequest{
+		Method: req.Method,
+		Path:   req.Path,
+		Params: params,
+		Query:  map[string]string{},
+		Header: map[string]string{},
+	}
+	for key := range req.Query {
+		data.Query[key] = req.Query.Get(key)
+	}
+	for key := range req.Header {
+		data.Header[key] = req.Header.Get(key)
+	}
+	if len(req.Body) > 0 {
+		json.Unmarshal(req.Body, &data.Body)
+	}
+
+	status := r.Status
+	if r.StatusTemplate != "" {
+		text, err := render(r.StatusTemplate, data)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if status, err = strconv.Atoi(strings.TrimSpace(text)); err != nil {
+			http.Error(w, fmt.Sprintf("stub rule status %q is not a number", text), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	headers := map[string]string{}
+	for key, value := range r.ReplyHeaders {
+		v, err := render(value, data)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		headers[key] = v
+	}
+
+	body, err := render(r.Body, data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	for key, value := range headers {
+		w.Header().Set(key, value)
+	}
+
+	w.WriteHeader(status)
+	w.Write([]byte(body))
+}
+
+func matchPath(template, path string) (map[string]string, bool) {
+	params := map[string]string{}
+	if template == "" || template == "*" {
+		return params, true
+	}
+
+	expected := strings.Split(strings.Trim(template, "/"), "/")
+	actual := strings.Split(strings.Trim(path, "/"), "/")
+	if len(expected) != len(actual) {
+		return nil, false
+	}
+
+	for i, segment := range expected {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") && actual[i] != "" {
+			params[segment[1:len(segment)-1]] = actual[i]
+		} else if segment != actual[i] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+func render(text string, data stubRequest) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	t, err := template.New("stub").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse stub template %q: %s", text, err)
+	}
+
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("cannot render stub template %q: %s", text, err)
+	}
+
+	return b.String(), nil
+}
+
+func (r *StubRule) checkTemplates() error {
+	texts := []string{r.StatusTemplate, r.Body}
+	for _, value := range r.ReplyHeaders {
+		texts = append(texts, value)
+	}
+
+	for _, text := range texts {
+		if err := checkTemplate(text); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func mustCheckTemplate(text string) {
+	if err := checkTemplate(text); err != nil {
+		panic(err.Error())
+	}
+}
+
+func checkTemplate(text string) error {
+	if !strings.Contains(text, "{{") {
+		return nil
+	}
+
+	t, err := template.New("stub").Parse(text)
+	if err != nil {
+		return fmt.Errorf("cannot parse stub template %q: %s", text, err)
+	}
+
+	if field := unknownField(t.Tree.Root); field != "" {
+		return fmt.Errorf("cannot parse stub template %q: unknown field %s", text, field)
+	}
+
+	return nil
+}
+
+var stubFields = map[string]bool{
+	"Method": true,
+	"Path":   true,
+	"Params": true,
+	"Query":  true,
+	"Header": true,
+	"Body":   true,
+}
+
+func unknownField(node parse.Node) string {
+	var nodes []parse.Node
+	switch n := node.(type) {
+	case *parse.FieldNode:
+		if !stubFields[n.Ident[0]] {
+			return n.Ident[0]
+		}
+	case *parse.ListNode:
+		if n != nil {
+			nodes = n.Nodes
+		}
+	case *parse.ActionNode:
+		nodes = []parse.Node{n.Pipe}
+	case *parse.PipeNode:
+		for _, cmd := range n.Cmds {
+			nodes = append(nodes, cmd.Args...)
+		}
+	case *parse.IfNode:
+		nodes = []parse.Node{n.Pipe, n.List, n.ElseList}
+	case *parse.RangeNode:
+		nodes = []parse.Node{n.Pipe, n.ElseList}
+	case *parse.WithNode:
+		nodes = []parse.Node{n.Pipe, n.ElseList}
+	}
+
+	for _, n := range nodes {
+		if field := unknownField(n); field != "" {
+			return field
+		}
+	}
+
+	return ""
+}
+
+var faults = map[string]bool{
+	"close":     true,
+	"malformed": true,
+}
+
+func fault(w http.ResponseWriter, kind string) {
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		panic("stub server cannot hijack the connection")
+	}
+
+	conn, buf, err := hj.Hijack()
+	if err != nil {
+		panic("stub server cannot hijack the connection")
+	}
+	defer conn.Close()
+
+	if kind == "malformed" {
+		buf.WriteString("HTTP/1.1 ???\r\n\r\n")
+		buf.Flush()
+	}
+}
+
+func normalizeYAML(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := map[string]interface{}{}
+		for key, value := range v {
+			m[key] = normalizeYAML(value)
+		}
+
+		return m
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for key, value := range v {
+			m[fmt.Sprintf("%v", key)] = normalizeYAML(value)
+		}
+
+		return m
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = normalizeYAML(item)
+		}
+
+		return items
+	}
+
+	return v
+}
@@ -0,0 +1,242 @@
+package assert
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type TestingT interface {
+	Helper()
+	Cleanup(func())
+	Errorf(format string, args ...interface{})
+}
+
+type ScenarioRun struct {
+	Vars *Vars
+
+	t        TestingT
+	cleanups []*scenarioStep
+	results  []StepResult
+}
+
+type ScenarioContext struct {
+	*Vars
+	Step    string
+	Attempt int
+}
+
+type StepResult struct {
+	Name     string
+	Status   string
+	Cleanup  bool
+	Attempts int
+	Resp     *http.Response
+	Failures []string
+}
+
+type StepOption func(*scenarioStep)
+
+type scenarioStep struct {
+	name      string
+	fn        func(*ScenarioContext) HttpJson
+	cleanup   bool
+	retries   int
+	delay     time.Duration
+	dependsOn []string
+}
+
+func Scenario(t TestingT) *ScenarioRun {
+	t.Helper()
+
+	s := &ScenarioRun{Vars: NewVars(), t: t}
+	t.Cleanup(s.finish)
+
+	return s
+}
+
+func Retries(n int, delay time.Duration) StepOption {
+	return func(step *scenarioStep) {
+		step.retries = n
+		step.delay = delay
+	}
+}
+
+func DependsOn(names ...string) StepOption {
+	return func(step *scenarioStep) {
+		step.dependsOn = append(step.dependsOn, names...)
+	}
+}
+
+func (s *ScenarioRun) Step(name string, fn func(*ScenarioContext) HttpJson, options ...StepOption) *ScenarioRun {
+	step := &scenarioStep{name: name, fn: fn}
+	for _, option := range options {
+		option(step)
+	}
+
+	if blocker, ok := s.blocker(step); ok {
+		s.results = append(s.results, StepResult{
+			Name:     name,
+			Status:   "skipped",
+			Failures: []string{"depends on " + blocker},
+		})
+
+		return s
+	}
+
+	s.results = append(s.results, s.run(step))
+	return s
+}
+
+func (s *ScenarioRun) Cleanup(name string, fn func(*ScenarioContext) HttpJson, options ...StepOption) *ScenarioRun {
+	step := &scenarioStep{name: name, fn: fn, cleanup: true}
+	for _, option := range options {
+		option(step)
+	}
+
+	s.cleanups = append(s.cleanups, step)
+	return s
+}
+
+func (s *ScenarioRun) Results() []StepResult {
+	return append([]StepResult(nil), s.results...)
+}
+
+func (s *ScenarioRun) Check() bool {
+	for _, r := range s.results {
+		if r.Status != "passed" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *ScenarioRun) Report() string {
+	var steps []string
+	for _, r := range s.results {
+		steps = append(steps, r.report())
+	}
+
+	return strings.Join(steps, "\n")
+}
+
+func (s *ScenarioRun) finish() {
+	for i := len(s.cleanups) - 1; i >= 0; i-- {
+		s.results = append(s.results, s.run(s.cleanups[i]))
+	}
+	s.cleanups = nil
+
+	if !s.Check() {
+		s.t.Helper()
+		s.t.Errorf("scenario failed:\n%s", s.Report())
+	}
+}
+
+func (s *ScenarioRun) blocker(step *scenarioStep) (string, bool) {
+	for _, r := range s.results {
+		if r.Cleanup || r.Status == "passed" {
+			continue
+		}
+
+		if len(step.dependsOn) == 0 {
+			return r.Name, true
+		}
+
+		for _, name := range step.dependsOn {
+			if name == r.Name {
+				return r.Name, true
+			}
+		}
+	}
+
+	for _, name := range step.dependsOn {
+		if !s.hasStep(name) {
+			panic(fmt.Sprintf("scenario has no step %q", name))
+		}
+	}
+
+	return "", false
+}
+
+func (s *ScenarioRun) hasStep(name string) bool {
+	for _, r := range s.results {
+		if r.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *ScenarioRun) run(step *scenarioStep) StepResult {
+	result := StepResult{Name: step.name, Cleanup: step.cleanup}
+
+	for attempt := 1; attempt <= step.retries+1; attempt++ {
+		if attempt > 1 && step.delay > 0 {
+			time.Sleep(step.delay)
+		}
+
+		ctx := &ScenarioContext{Vars: s.Vars, Step: step.name, Attempt: attempt}
+		h, failure := runStep(step.fn, ctx)
+
+		result.Attempts = attempt
+		result.Resp = h.Resp
+		result.Failures = h.Failures
+		if failure != "" {
+			result.Failures = append(result.Failures, failure)
+		} else if !h.Check() && len(h.Failures) == 0 {
+			result.Failures = append(result.Failures, "step returned a failed assertion")
+		}
+
+		if len(result.Failures) == 0 {
+			result.Status = "passed"
+			return result
+		}
+	}
+
+	result.Status = "failed"
+	return result
+}
+
+func runStep(fn func(*ScenarioContext) HttpJson, ctx *ScenarioContext) (h HttpJson, failure string) {
+	defer func() {
+		if r := recover(); r != nil {
+			failure = fmt.Sprintf("step panicked: %v", r)
+		}
+	}()
+
+	return fn(ctx), ""
+}
+
+func (r StepResult) report() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s", strings.ToUpper(r.Status), r.Name)
+	if r.Cleanup {
+		b.WriteString(" (cleanup)")
+	}
+	if r.Attempts > 1 {
+		fmt.Fprintf(&b, " (%d attempts)", r.Attempts)
+	}
+
+	var lines []string
+	if r.Resp != nil {
+		if r.Resp.Request != nil {
+			lines = append(lines, requestSummary(r.Resp.Request))
+		}
+
+		response := fmt.Sprintf("response: %d %s", r.Resp.StatusCode, http.StatusText(r.Resp.StatusCode))
+		if body := readBody(r.Resp); len(body) > 0 {
+			response += "\n  body: " + snippet(body)
+		}
+		lines = append(lines, response)
+	}
+	lines = append(lines, r.Failures...)
+
+	for _, line := range lines {
+		b.WriteString("\n  " + strings.ReplaceAll(line, "\n", "\n  "))
+	}
+
+	return b.String()
+}
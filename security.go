@@ -0,0 +1,197 @@
+package assert
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ohmymajo/http-assert/pkg/header"
+)
+
+type SecurityProfile struct {
+	HSTSMinMaxAge             time.Duration
+	HSTSIncludeSubDomains     bool
+	CSPRequired               []string
+	CSPForbidden              map[string][]string
+	ContentTypeOptions        bool
+	ReferrerPolicies          []string
+	PermissionsPolicy         bool
+	CrossOriginOpenerPolicy   []string
+	CrossOriginEmbedderPolicy []string
+	CrossOriginResourcePolicy []string
+	ForbiddenHeaders          []string
+}
+
+var OWASPSecurityProfile = SecurityProfile{
+	HSTSMinMaxAge:         365 * 24 * time.Hour,
+	HSTSIncludeSubDomains: true,
+	CSPRequired:           []string{"default-src", "frame-ancestors"},
+	CSPForbidden: map[string][]string{
+		"script-src": {"'unsafe-inline'", "'unsafe-eval'", "*"},
+		"object-src": {"*"},
+	},
+	ContentTypeOptions:        true,
+	ReferrerPolicies:          []string{"no-referrer", "same-origin", "strict-origin", "strict-origin-when-cross-origin"},
+	PermissionsPolicy:         true,
+	CrossOriginOpenerPolicy:   []string{"same-origin"},
+	CrossOriginEmbedderPolicy: []string{"require-corp", "credentialless"},
+	CrossOriginResourcePolicy: []string{"same-origin", "same-site"},
+	ForbiddenHeaders:          []string{"Server", "X-Powered-By", "X-AspNet-Version"},
+}
+
+func (h Http) AssertSecurityHeaders(profile SecurityProfile) HttpJson {
+	hj := h.AssertHeader()
+	return hj.ruleResult(securityRules(hj.Header, profile))
+}
+
+func (h HttpJson) ruleResult(failures []string) HttpJson {
+	if !h.evaluate() {
+		return h
+	}
+
+	for _, failure := range failures {
+		h.Failures = append(h.Failures[:len(h.Failures):len(h.Failures)], h.Type+": "+failure)
+	}
+
+	h.AssertCorrect = h.AssertCorrect && len(failures) == 0
+	return h
+}
+
+func securityRules(h *http.Header, profile SecurityProfile) []string {
+	var failures []string
+
+	if profile.HSTSMinMaxAge > 0 || profile.HSTSIncludeSubDomains {
+		failures = append(failures, hstsRules(h, profile)...)
+	}
+
+	if len(profile.CSPRequired) > 0 || len(profile.CSPForbidden) > 0 {
+		failures = append(failures, cspRules(h, profile)...)
+	}
+
+	if profile.ContentTypeOptions && !strings.EqualFold(strings.TrimSpace(h.Get("X-Content-Type-Options")), "nosniff") {
+		failures = append(failures, fmt.Sprintf("X-Content-Type-Options must be nosniff, got %q", h.Get("X-Content-Type-Options")))
+	}
+
+	if len(profile.ReferrerPolicies) > 0 {
+		tokens := header.SplitList(h.Values("Referrer-Policy"))
+		if len(tokens) == 0 || !containsFold(profile.ReferrerPolicies, tokens[len(tokens)-1]) {
+			failures = append(failures, fmt.Sprintf("Referrer-Policy must be one of %q, got %q", profile.ReferrerPolicies, h.Get("Referrer-Policy")))
+		}
+	}
+
+	if profile.PermissionsPolicy && h.Get("Permissions-Policy") == "" {
+		failures = append(failures, "Permissions-Policy is missing")
+	}
+
+	policies := []struct {
+		name    string
+		allowed []string
+	}{
+		{"Cross-Origin-Opener-Policy", profile.CrossOriginOpenerPolicy},
+		{"Cross-Origin-Embedder-Policy", profile.CrossOriginEmbedderPolicy},
+		{"Cross-Origin-Resource-Policy", profile.CrossOriginResourcePolicy},
+	}
+	for _, p := range policies {
+		if len(p.allowed) == 0 {
+			continue
+		}
+
+		value, _, _ := strings.Cut(h.Get(p.name), ";")
+		if !containsFold(p.allowed, strings.TrimSpace(value)) {
+			failures = append(failures, fmt.Sprintf("%s must be one of %q, got %q", p.name, p.allowed, h.Get(p.name)))
+		}
+	}
+
+	for _, name := range profile.ForbiddenHeaders {
+		if v := h.Get(name); v != "" {
+			failures = append(failures, fmt.Sprintf("%s header leaks %q", name, v))
+		}
+	}
+
+	return failures
+}
+
+func hstsRules(h *http.Header, profile SecurityProfile) []string {
+	value := h.Get("Strict-Transport-Security")
+	if value == "" {
+		return []string{"Strict-Transport-Security is missing"}
+	}
+
+	var failures []string
+	params := header.ParseParameters(value)
+
+	seconds, err := strconv.Atoi(params["max-age"])
+	if err != nil {
+		failures = append(failures, fmt.Sprintf("Strict-Transport-Security max-age is invalid in %q", value))
+	} else if maxAge := time.Duration(seconds) * time.Second; maxAge < profile.HSTSMinMaxAge {
+		failures = append(failures, fmt.Sprintf("Strict-Transport-Security max-age %s is below %s", maxAge, profile.HSTSMinMaxAge))
+	}
+
+	if _, ok := params["includesubdomains"]; profile.HSTSIncludeSubDomains && !ok {
+		failures = append(failures, "Strict-Transport-Security is missing includeSubDomains")
+	}
+
+	return failures
+}
+
+func cspRules(h *http.Header, profile SecurityProfile) []string {
+	policies := header.ParseCSP(h.Values("Content-Security-Policy"))
+	if len(policies) == 0 {
+		return []string{"Content-Security-Policy is missing"}
+	}
+
+	var failures []string
+	for _, directive := range profile.CSPRequired {
+		found := false
+		for _, policy := range policies {
+			if _, ok := policy[directive]; ok {
+				found = true
+			}
+		}
+
+		if !found {
+			failures = append(failures, fmt.Sprintf("Content-Security-Policy is missing %s", directive))
+		}
+	}
+
+	directives := make([]string, 0, len(profile.CSPForbidden))
+	for directive := range profile.CSPForbidden {
+		directives = append(directives, directive)
+	}
+	sort.Strings(directives)
+
+	for _, directive := range directives {
+		for _, source := range profile.CSPForbidden[directive] {
+			allowed := true
+			for _, policy := range policies {
+				sources, ok := policy[directive]
+				if !ok && strings.HasSuffix(directive, "-src") {
+					sources, ok = policy["default-src"]
+				}
+
+				if ok && !containsFold(sources, source) {
+					allowed = false
+				}
+			}
+
+			if allowed {
+				failures = append(failures, fmt.Sprintf("Content-Security-Policy %s allows %s", directive, source))
+			}
+		}
+	}
+
+	return failures
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,274 @@
+package assert
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Spec struct {
+	BaseURL      string                     `json:"baseURL" yaml:"baseURL"`
+	Headers      map[string]string          `json:"headers" yaml:"headers"`
+	Environments map[string]SpecEnvironment `json:"environments" yaml:"environments"`
+	Vars         map[string]interface{}     `json:"vars" yaml:"vars"`
+	Cases        []SpecCase                 `json:"cases" yaml:"cases"`
+
+	once  sync.Once
+	store *Vars
+}
+
+type SpecEnvironment struct {
+	BaseURL string            `json:"baseURL" yaml:"baseURL"`
+	Headers map[string]string `json:"headers" yaml:"headers"`
+}
+
+type SpecCase struct {
+	Name    string            `json:"name" yaml:"name"`
+	Request SpecRequest       `json:"request" yaml:"request"`
+	Expect  SpecExpect        `json:"expect" yaml:"expect"`
+	Capture map[string]string `json:"capture" yaml:"capture"`
+}
+
+type SpecRequest struct {
+	Method  string            `json:"method" yaml:"method"`
+	Path    string            `json:"path" yaml:"path"`
+	Query   map[string]string `json:"query" yaml:"query"`
+	Headers map[string]string `json:"headers" yaml:"headers"`
+	Body    string            `json:"body" yaml:"body"`
+	JSON    interface{}       `json:"json" yaml:"json"`
+}
+
+type SpecExpect struct {
+	Status  int        `json:"status" yaml:"status"`
+	Headers []SpecRule `json:"headers" yaml:"headers"`
+	Body    []SpecRule `json:"body" yaml:"body"`
+}
+
+type SpecRule struct {
+	Path  string      `json:"path" yaml:"path"`
+	Op    string      `json:"op" yaml:"op"`
+	Value interface{} `json:"value" yaml:"value"`
+}
+
+type SpecResult struct {
+	Name     string
+	Passed   bool
+	Report   string
+	Duration time.Duration
+}
+
+func LoadSpec(path string) (*Spec, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec Spec
+	if isYAML(path) {
+		err = yaml.Unmarshal(b, &spec)
+	} else {
+		err = json.Unmarshal(b, &spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse spec %s: %s", path, err)
+	}
+
+	for i, c := range spec.Cases {
+		if c.Name == "" {
+			spec.Cases[i].Name = strings.TrimSpace(c.Request.Method + " " + c.Request.Path)
+		}
+
+		for _, rule := range append(c.Expect.Headers, c.Expect.Body...) {
+			if _, ok := specOps[rule.Op]; rule.Op != "" && !ok {
+				return nil, fmt.Errorf("cannot parse spec %s: case %q has unknown op %q", path, spec.Cases[i].Name, rule.Op)
+			}
+		}
+	}
+
+	return &spec, nil
+}
+
+func (s *Spec) Use(environment string) error {
+	env, ok := s.Environments[environment]
+	if !ok {
+		return fmt.Errorf("spec has no environment %q", environment)
+	}
+
+	if env.BaseURL != "" {
+		s.BaseURL = env.BaseURL
+	}
+
+	if len(env.Headers) > 0 && s.Headers == nil {
+		s.Headers = map[string]string{}
+	}
+	for key, value := range env.Headers {
+		s.Headers[key] = value
+	}
+
+	return nil
+}
+
+func (s *Spec) Variables() *Vars {
+	s.once.Do(func() {
+		s.store = NewVars()
+		for name, value := range s.Vars {
+			s.store.Set(name, normalizeYAML(value))
+		}
+	})
+
+	return s.store
+}
+
+func RunSpecFile(t *testing.T, path string) {
+	t.Helper()
+
+	spec, err := LoadSpec(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range spec.Cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			if r := spec.Run(c, http.DefaultClient); !r.Passed {
+				t.Error(r.Report)
+			}
+		})
+	}
+}
+
+func (s *Spec) Run(c SpecCase, client *http.Client) (result SpecResult) {
+	start := time.Now()
+	result.Name = c.Name
+
+	defer func() {
+		if r := recover(); r != nil {
+			result.Passed = false
+			result.Report = fmt.Sprintf("%v", r)
+		}
+
+		result.Duration = time.Since(start)
+	}()
+
+	method := c.Request.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	vars := s.Variables()
+	req := Request(method, strings.TrimSuffix(s.BaseURL, "/")+c.Request.Path).Client(client).Vars(vars)
+	for key, value := range s.Headers {
+		req.Header(key, value)
+	}
+	for key, value := range c.Request.Headers {
+		req.header.Set(key, value)
+	}
+	for key, value := range c.Request.Query {
+		req.Query(key, value)
+	}
+
+	if c.Request.JSON != nil {
+		req.JSON(normalizeYAML(c.Request.JSON))
+	} else if c.Request.Body != "" {
+		req.Body(req.header.Get("Content-Type"), []byte(c.Request.Body))
+	}
+
+	h := req.Do().AssertHeader().Soft()
+	if c.Expect.Status != 0 {
+		h = h.Status(c.Expect.Status)
+	}
+
+	for _, rule := range c.Expect.Headers {
+		h = rule.apply(h, vars)
+	}
+
+	if len(c.Expect.Body) > 0 || len(c.Capture) > 0 {
+		h = specBody(h, c, vars)
+	}
+
+	result.Passed = h.Check()
+	result.Report = h.Report()
+	return result
+}
+
+var specOps = map[string]func(h HttpJson, path string, value interface{}) HttpJson{
+	"exists": func(h HttpJson, path string, value interface{}) HttpJson {
+		return h.Has(path)
+	},
+	"eq": func(h HttpJson, path string, value interface{}) HttpJson {
+		return h.Where(path, value)
+	},
+	"ne": func(h HttpJson, path string, value interface{}) HttpJson {
+		return h.WhereNot(path, value)
+	},
+	"gt": func(h HttpJson, path string, value interface{}) HttpJson {
+		return h.WhereGt(path, value)
+	},
+	"gte": func(h HttpJson, path string, value interface{}) HttpJson {
+		return h.WhereGte(path, value)
+	},
+	"lt": func(h HttpJson, path string, value interface{}) HttpJson {
+		return h.WhereLt(path, value)
+	},
+	"lte": func(h HttpJson, path string, value interface{}) HttpJson {
+		return h.WhereLte(path, value)
+	},
+	"type": func(h HttpJson, path string, value interface{}) HttpJson {
+		return h.WhereType(path, fmt.Sprintf("%v", value))
+	},
+	"length": func(h HttpJson, path string, value interface{}) HttpJson {
+		n, ok := value.(int)
+		if f, isFloat := value.(float64); isFloat {
+			n, ok = int(f), f == float64(int(f))
+		}
+		if !ok {
+			panic(fmt.Sprintf("length of %q must be an integer", path))
+		}
+
+		return h.HasLength(path, n)
+	},
+}
+
+func specBody(h HttpJson, c SpecCase, vars *Vars) (result HttpJson) {
+	defer func() {
+		if r := recover(); r != nil {
+			h.Type = "body"
+			result = h.result(false, "%v", r)
+		}
+	}()
+
+	h = h.AssertBody()
+	for _, rule := range c.Expect.Body {
+		h = rule.apply(h, vars)
+	}
+
+	for name, path := range c.Capture {
+		h = h.CaptureAs(vars, name, path)
+	}
+
+	return h
+}
+
+func (r SpecRule) apply(h HttpJson, vars *Vars) HttpJson {
+	op := r.Op
+	if op == "" {
+		op = "eq"
+	}
+
+	value := normalizeYAML(r.Value)
+	if text, ok := value.(string); ok {
+		value = vars.resolve(text)
+	}
+	if h.Type == "header" && (op == "eq" || op == "ne") {
+		value = fmt.Sprintf("%v", value)
+	}
+
+	return specOps[op](h, r.Path, value)
+}
@@ -0,0 +1,186 @@
+package assert
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+var statusHeaders = []string{"Content-Type", "Location", "Retry-After", "WWW-Authenticate", "X-Request-Id"}
+
+func (h Http) Status(statusCode int) HttpJson {
+	return h.AssertHeader().Status(statusCode)
+}
+
+func (h Http) IsInformational() HttpJson {
+	return h.AssertHeader().IsInformational()
+}
+
+func (h Http) IsSuccess() HttpJson {
+	return h.AssertHeader().IsSuccess()
+}
+
+func (h Http) IsRedirect() HttpJson {
+	return h.AssertHeader().IsRedirect()
+}
+
+func (h Http) IsClientError() HttpJson {
+	return h.AssertHeader().IsClientError()
+}
+
+func (h Http) IsServerError() HttpJson {
+	return h.AssertHeader().IsServerError()
+}
+
+func (h Http) StatusIn(statusCodes ...int) HttpJson {
+	return h.AssertHeader().StatusIn(statusCodes...)
+}
+
+func (h Http) StatusBetween(min, max int) HttpJson {
+	return h.AssertHeader().StatusBetween(min, max)
+}
+
+func (h HttpJson) Status(statusCode int) HttpJson {
+	return h.StatusIn(statusCode)
+}
+
+func (h HttpJson) IsInformational() HttpJson {
+	return h.statusRange(100, 199, "1xx")
+}
+
+func (h HttpJson) IsSuccess() HttpJson {
+	return h.statusRange(200, 299, "2xx")
+}
+
+func (h HttpJson) IsRedirect() HttpJson {
+	return h.statusRange(300, 399, "3xx")
+}
+
+func (h HttpJson) IsClientError() HttpJson {
+	return h.statusRange(400, 499, "4xx")
+}
+
+func (h HttpJson) IsServerError() HttpJson {
+	return h.statusRange(500, 599, "5xx")
+}
+
+func (h HttpJson) StatusIn(statusCodes ...int) HttpJson {
+	var correct bool
+	if h.evaluate() && h.Resp != nil {
+		for _, code := range statusCodes {
+			if h.Resp.StatusCode == code {
+				correct = true
+				break
+			}
+		}
+	}
+
+	codes := make([]string, len(statusCodes))
+	for i, code := range statusCodes {
+		codes[i] = fmt.Sprintf("%d", code)
+	}
+
+	return h.statusResult(correct, strings.Join(codes, " or "))
+}
+
+func (h HttpJson) StatusBetween(min, max int) HttpJson {
+	return h.statusRange(min, max, fmt.Sprintf("%d-%d", min, max))
+}
+
+func (h HttpJson) AssertHeader() HttpJson {
+	h.Type = "header"
+	if h.Resp != nil {
+		h.Header = &h.Resp.Header
+	}
+	return h
+}
+
+func (h HttpJson) AssertBody() HttpJson {
+	h.Type = "body"
+	h.Body = nil
+	if h.Resp == nil {
+		return h.result(false, "no response")
+	}
+
+	h.Header = &h.Resp.Header
+
+	body, err := decodeBody(h.Resp)
+	if err != nil {
+		return h.result(false, "%s", err)
+	}
+
+	h.Body = body
+	return h
+}
+
+func (h HttpJson) AssertCookies() HttpJson {
+	h.Type = "cookie"
+	if h.Resp != nil {
+		h.Header = &h.Resp.Header
+	}
+	return h
+}
+
+func (h HttpJson) statusRange(min, max int, expected string) HttpJson {
+	var correct bool
+	if h.evaluate() && h.Resp != nil {
+		correct = h.Resp.StatusCode >= min && h.Resp.StatusCode <= max
+	}
+
+	return h.statusResult(correct, expected)
+}
+
+func (h HttpJson) statusResult(correct bool, expected string) HttpJson {
+	if h.evaluate() && !correct {
+		h.Failures = append(h.Failures[:len(h.Failures):len(h.Failures)], statusFailure(h.Resp, expected))
+	}
+
+	h.AssertCorrect = h.AssertCorrect && correct
+	return h
+}
+
+func statusFailure(resp *http.Response, expected string) string {
+	if resp == nil {
+		return fmt.Sprintf("status: expected %s, got no response", expected)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "status: expected %s, got %d %s", expected, resp.StatusCode, http.StatusText(resp.StatusCode))
+
+	for _, name := range statusHeaders {
+		if v := resp.Header.Get(name); v != "" {
+			fmt.Fprintf(&b, "\n  %s: %s", name, v)
+		}
+	}
+
+	if body := readBody(resp); len(body) > 0 {
+		fmt.Fprintf(&b, "\n  body: %s", snippet(body))
+	}
+
+	return b.String()
+}
+
+func snippet(body []byte) string {
+	if len(body) > 200 {
+		return string(body[:200]) + "..."
+	}
+
+	return string(body)
+}
+
+func readBody(resp *http.Response) []byte {
+	if resp.Body == nil {
+		return nil
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		panic("cannot read the response body")
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+	return b
+}
@@ -0,0 +1,163 @@
+package assert
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+)
+
+type StubServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []RecordedRequest
+	rules    []*StubRule
+}
+
+type RecordedRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+func NewStubServer() *StubServer {
+	s := &StubServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+func (s *StubServer) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	req := RecordedRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	rules := append([]*StubRule(nil), s.rules...)
+	s.mu.Unlock()
+
+	if len(rules) == 0 {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	for _, rule := range rules {
+		if params, ok := rule.match(req); ok {
+			rule.reply(w, req, params)
+			return
+		}
+	}
+
+	http.Error(w, "no stub rule matches "+req.Method+" "+req.Path, http.StatusNotFound)
+}
+
+func (s *StubServer) Requests() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]RecordedRequest(nil), s.requests...)
+}
+
+func (s *StubServer) Request(i int) RecordedRequest {
+	requests := s.Requests()
+	if i < 0 || i >= len(requests) {
+		panic("stub server did not receive request " + strconv.Itoa(i))
+	}
+
+	return requests[i]
+}
+
+func (s *StubServer) Calls(method, path string) []RecordedRequest {
+	var calls []RecordedRequest
+	for _, r := range s.Requests() {
+		if r.Method == method && r.Path == path {
+			calls = append(calls, r)
+		}
+	}
+
+	return calls
+}
+
+func (s *StubServer) AssertCallCount(method, path string, count int) HttpJson {
+	calls := len(s.Calls(method, path))
+	return stubResult(calls == count, "AssertCallCount(%q, %q, %d): got %d", method, path, count, calls)
+}
+
+func (s *StubServer) AssertCalled(method, path string) HttpJson {
+	return stubResult(len(s.Calls(method, path)) > 0, "AssertCalled(%q, %q)", method, path)
+}
+
+func (s *StubServer) AssertCallOrder(calls ...string) HttpJson {
+	var received []string
+	for _, r := range s.Requests() {
+		received = append(received, r.Method+" "+r.Path)
+	}
+
+	i := 0
+	for _, call := range received {
+		if i < len(calls) && calls[i] == call {
+			i++
+		}
+	}
+
+	return stubResult(i == len(calls), "AssertCallOrder(%q): got %q", calls, received)
+}
+
+func (s *StubServer) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = nil
+}
+
+func (r RecordedRequest) AssertHeader() HttpJson {
+	return HttpJson{
+		Type:          "header",
+		Header:        &r.Header,
+		AssertCorrect: true,
+	}
+}
+
+func (r RecordedRequest) AssertQuery() HttpJson {
+	return HttpJson{
+		Type:          "query",
+		Header:        &r.Header,
+		Query:         r.Query,
+		AssertCorrect: true,
+	}
+}
+
+func (r RecordedRequest) AssertBody() HttpJson {
+	resp := http.Response{
+		Header: r.Header,
+		Body:   io.NopCloser(bytes.NewReader(r.Body)),
+	}
+
+	return New(&resp).AssertBody()
+}
+
+func stubResult(correct bool, format string, args ...interface{}) HttpJson {
+	h := HttpJson{Type: "stub", AssertCorrect: true}
+	return h.result(correct, format, args...)
+}
+
+func queryValue(query url.Values, fieldName string) (string, bool) {
+	values := query[fieldName]
+	if len(values) == 0 {
+		return "", false
+	}
+
+	return values[0], true
+}
@@ -0,0 +1,134 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+
+	assert "github.com/ohmymajo/http-assert"
+)
+
+func TestAssertWhereField(t *testing.T) {
+	b := []byte(`{"startDate": "2024-01-01T00:00:00Z", "endDate": "2024-02-01T00:00:00Z", "min": 1, "max": 2.5}`)
+
+	resp := http.Response{
+		Body: io.NopCloser(bytes.NewReader(b)),
+	}
+
+	http := assert.New(&resp)
+	httpBody := http.AssertBody()
+	val := httpBody.
+		WhereField("endDate", "gt", "startDate").
+		WhereField("min", "lt", "max").
+		Check()
+
+	if !val {
+		t.Fail()
+	}
+}
+
+func TestAssertWhereFieldFail(t *testing.T) {
+	b := []byte(`{"startDate": "2024-02-01T00:00:00Z", "endDate": "2024-01-01T00:00:00Z"}`)
+
+	resp := http.Response{
+		Body: io.NopCloser(bytes.NewReader(b)),
+	}
+
+	http := assert.New(&resp)
+	httpBody := http.AssertBody()
+	val := httpBody.
+		WhereField("endDate", "gt", "startDate").
+		Check()
+
+	if val {
+		t.Fail()
+	}
+}
+
+func TestAssertWhereAggregate(t *testing.T) {
+	b := []byte(`{"items": [{"amount": 10}, {"amount": 2.5}, {"amount": 7}], "meta": {"total": 3}, "summary": {"total": 19.5}}`)
+
+	resp := http.Response{
+		Body: io.NopCloser(bytes.NewReader(b)),
+	}
+
+	http := assert.New(&resp)
+	httpBody := http.AssertBody()
+	val := httpBody.
+		WhereAggregate("count", "items", "eq", 3).
+		WhereAggregate("min", "items.*.amount", "eq", 2.5).
+		WhereAggregate("max", "items.*.amount", "eq", 10).
+		WhereAggregate("avg", "items.*.amount", "eq", 6.5).
+		WhereAggregateField("count", "items", "eq", "meta.total").
+		WhereAggregateField("sum", "items.*.amount", "eq", "summary.total").
+		Check()
+
+	if !val {
+		t.Fail()
+	}
+}
+
+func TestAssertWhereAggregateFail(t *testing.T) {
+	b := []byte(`{"items": [{"amount": 10}, {"amount": 7}], "summary": {"total": 20}}`)
+
+	resp := http.Response{
+		Body: io.NopCloser(bytes.NewReader(b)),
+	}
+
+	http := assert.New(&resp)
+	httpBody := http.AssertBody()
+	val := httpBody.
+		WhereAggregateField("sum", "items.*.amount", "eq", "summary.total").
+		Check()
+
+	if val {
+		t.Fail()
+	}
+}
+
+func TestAssertWhereAggregateUnknown(t *testing.T) {
+	b := []byte(`{"items": [{"amount": 10}, {"amount": 7}], "summary": {"total": 17}}`)
+
+	resp := http.Response{
+		Body: io.NopCloser(bytes.NewReader(b)),
+	}
+
+	http := assert.New(&resp)
+	val := http.AssertBody().
+		Soft().
+		WhereAggregate("summ", "items.*.amount", "eq", 17).
+		WhereAggregateField("summ", "items.*.amount", "eq", "summary.total").
+		WhereAggregate("sum", "items.*.amount", "eq", 17)
+
+	if val.Check() || len(val.Failures) != 2 {
+		t.Error(val.Report())
+	}
+
+	if val.Failures[0] != `body: WhereAggregate("summ", "items.*.amount", "eq", 17): unknown aggregate function` {
+		t.Error(val.Failures[0])
+	}
+}
+
+func TestAssertWhereAggregateWildcardArrays(t *testing.T) {
+	for _, tc := range []struct {
+		body  string
+		count int
+	}{
+		{`{"items": [{"tags": ["a", "b", "c"]}]}`, 1},
+		{`{"items": [{"tags": ["a", "b", "c"]}, {"tags": ["d"]}]}`, 2},
+	} {
+		resp := http.Response{
+			Body: io.NopCloser(bytes.NewReader([]byte(tc.body))),
+		}
+
+		val := assert.New(&resp).AssertBody().
+			WhereAggregate("count", "items.*.tags", "eq", tc.count).
+			WhereAggregate("count", "items.0.tags", "eq", 3).
+			Check()
+
+		if !val {
+			t.Error(tc.body)
+		}
+	}
+}
@@ -205,7 +205,7 @@ func TestAssertHasLength(t *testing.T) {
 }
 
 func TestAssertWhereGte(t *testing.T) {
-	b := []byte(`{"int": 2}`)
+	b := []byte(`{"int": 2, "obj": {"int": 2}}`)
 
 	resp := http.Response{
 		Body: io.NopCloser(bytes.NewReader(b)),
@@ -214,8 +214,8 @@ func TestAssertWhereGte(t *testing.T) {
 	http := assert.New(&resp)
 	httpBody := http.AssertBody()
 	val := httpBody.
-		WhereGte("int", 3).
-		WhereGte("obj.int", 3).
+		WhereGte("int", 2).
+		WhereGte("obj.int", 2).
 		Check()
 
 	if !val {
@@ -252,8 +252,8 @@ func TestAssertWhereGt(t *testing.T) {
 	http := assert.New(&resp)
 	httpBody := http.AssertBody()
 	val := httpBody.
-		WhereGt("int", 3).
-		WhereGt("obj.int", 3).
+		WhereGt("int", 1).
+		WhereGt("obj.int", 1).
 		Check()
 
 	if !val {
@@ -290,8 +290,8 @@ func TestAssertWhereLte(t *testing.T) {
 	http := assert.New(&resp)
 	httpBody := http.AssertBody()
 	val := httpBody.
-		WhereLte("int", 1).
-		WhereLte("obj.int", 1).
+		WhereLte("int", 2).
+		WhereLte("obj.int", 2).
 		Check()
 
 	if !val {
@@ -309,7 +309,7 @@ func TestAssertWhereLteFail(t *testing.T) {
 	http := assert.New(&resp)
 	httpBody := http.AssertBody()
 	val := httpBody.
-		WhereLte("int", 3).
+		WhereLte("int", 1).
 		Check()
 
 	if val {
@@ -327,8 +327,8 @@ func TestAssertWhereLt(t *testing.T) {
 	http := assert.New(&resp)
 	httpBody := http.AssertBody()
 	val := httpBody.
-		WhereLt("int", 1).
-		WhereLt("obj.int", 1).
+		WhereLt("int", 3).
+		WhereLt("obj.int", 3).
 		Check()
 
 	if !val {
@@ -353,3 +353,40 @@ func TestAssertWhereLtFail(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestAssertReport(t *testing.T) {
+	header := http.Header{}
+	header.Add("x-test-value", "test")
+
+	resp := http.Response{
+		Header: header,
+	}
+
+	http := assert.New(&resp)
+	val := http.AssertHeader().
+		Has("x-test-value").
+		Where("x-test-value", "123").
+		Has("x-missing")
+
+	if val.Report() != `header: Where("x-test-value", "123")` {
+		t.Error(val.Report())
+	}
+}
+
+func TestAssertHeaderWhereNot(t *testing.T) {
+	header := http.Header{}
+	header.Add("x-test-value", "test")
+
+	resp := http.Response{
+		Header: header,
+	}
+
+	http := assert.New(&resp)
+	if !http.AssertHeader().WhereNot("x-test-value", "other").Check() {
+		t.Error("different value")
+	}
+
+	if http.AssertHeader().WhereNot("x-test-value", "test").Check() {
+		t.Error("same value")
+	}
+}
@@ -0,0 +1,120 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	assert "github.com/ohmymajo/http-assert"
+)
+
+func TestAssertCache(t *testing.T) {
+	header := http.Header{}
+	header.Add("Cache-Control", "public, max-age=3600, s-maxage=86400")
+	header.Add("Cache-Control", "stale-while-revalidate=60, immutable")
+	header.Add("ETag", `W/"abc123"`)
+	header.Add("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+	header.Add("Age", "10")
+	header.Add("Vary", "Accept-Encoding")
+
+	resp := http.Response{
+		Header: header,
+	}
+
+	http := assert.New(&resp)
+	val := http.AssertCache().
+		IsCacheable().
+		IsImmutable().
+		MaxAgeAtLeast(time.Hour).
+		MaxAgeAtMost(2 * time.Hour).
+		SharedMaxAgeAtLeast(24 * time.Hour).
+		StaleWhileRevalidateAtLeast(time.Minute).
+		AgeAtMost(time.Minute).
+		HasETag().
+		HasLastModified().
+		VaryIncludes("accept-encoding").
+		Check()
+
+	if !val {
+		t.Fail()
+	}
+}
+
+func TestAssertCacheNotStored(t *testing.T) {
+	header := http.Header{}
+	header.Add("Cache-Control", "no-store, private")
+
+	resp := http.Response{
+		Header: header,
+	}
+
+	http := assert.New(&resp)
+	if !http.AssertCache().NotStored().IsPrivate().Check() {
+		t.Fail()
+	}
+
+	if http.AssertCache().IsCacheable().Check() {
+		t.Fail()
+	}
+
+	if http.AssertCache().MaxAgeAtLeast(time.Second).Check() {
+		t.Fail()
+	}
+}
+
+func TestAssertCachePrivateNotShared(t *testing.T) {
+	header := http.Header{}
+	header.Add("Cache-Control", "private, max-age=600")
+
+	resp := http.Response{
+		Header: header,
+	}
+
+	http := assert.New(&resp)
+	if http.AssertCache().IsCacheable().Check() {
+		t.Error("private responses should not be cacheable by shared caches")
+	}
+
+	if !http.AssertCache().IsPrivate().MaxAgeAtLeast(10 * time.Minute).Check() {
+		t.Fail()
+	}
+}
+
+func TestAssertCacheMaxAgeOverridesExpires(t *testing.T) {
+	for _, tc := range []struct {
+		cacheControl string
+		cacheable    bool
+	}{
+		{"max-age=0", false},
+		{"public, max-age=0", false},
+		{"s-maxage=0, max-age=600", false},
+		{"s-maxage=600, max-age=0", true},
+		{"", true},
+	} {
+		header := http.Header{}
+		header.Add("Cache-Control", tc.cacheControl)
+		header.Add("Expires", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+
+		resp := http.Response{
+			Header: header,
+		}
+
+		if assert.New(&resp).AssertCache().IsCacheable().Check() != tc.cacheable {
+			t.Error(tc.cacheControl)
+		}
+	}
+}
+
+func TestAssertCacheDurationReport(t *testing.T) {
+	header := http.Header{}
+	header.Add("Cache-Control", "max-age=60")
+
+	resp := http.Response{
+		Header: header,
+	}
+
+	val := assert.New(&resp).AssertCache().MaxAgeAtLeast(10 * time.Minute)
+	if val.Check() || val.Failures[0] != "header: MaxAgeAtLeast(10m0s)" {
+		t.Error(val.Failures)
+	}
+}
@@ -0,0 +1,175 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	assert "github.com/ohmymajo/http-assert"
+)
+
+func orderServer() *assert.StubServer {
+	server := assert.NewStubServer()
+	server.On("POST", "/orders").
+		Reply(http.StatusCreated).
+		ReplyHeader("Location", "/orders/42").
+		ReplyHeader("X-Count", "3").
+		ReplyJSON(map[string]interface{}{"data": map[string]interface{}{"id": 42, "items": []string{"pen"}}})
+	server.On("GET", "/orders/{id}").
+		WithQuery("trace", "t-1").
+		WithHeader("X-Order", "42").
+		ReplyHeader("Content-Type", "application/json").
+		ReplyBody(`{"id": {{.Params.id}}}`)
+	server.On("PUT", "/orders/{id}").
+		WithBody(func(h assert.HttpJson) assert.HttpJson { return h.Where("id", "42") }).
+		Reply(http.StatusNoContent)
+
+	return server
+}
+
+func TestCapture(t *testing.T) {
+	server := orderServer()
+	defer server.Close()
+
+	var id int
+	var items []string
+	var location string
+	var count int
+	vars := assert.NewVars()
+	vars.Set("trace", "t-1")
+
+	resp := assert.Post(server.URL + "/orders").Do()
+	if !resp.AssertBody().Capture("data.id", &id).Capture("data.items", &items).CaptureAs(vars, "orderId", "data.id").Check() {
+		t.Fatal("body capture")
+	}
+
+	if !resp.AssertHeader().Capture("Location", &location).Capture("X-Count", &count).Check() {
+		t.Fatal("header capture")
+	}
+
+	if id != 42 || len(items) != 1 || location != "/orders/42" || count != 3 {
+		t.Fatal(id, items, location, count)
+	}
+
+	got := assert.Get(server.URL+"/orders/{{.orderId}}").
+		Vars(vars).
+		Query("trace", "{{.trace}}").
+		Header("X-Order", "{{.orderId}}").
+		Do()
+	if !got.Status(http.StatusOK).AssertBody().Where("id", 42).Check() {
+		t.Error(got.AssertHeader().Status(http.StatusOK).Report())
+	}
+
+	put := assert.Put(server.URL+"/orders/{id}").
+		Vars(vars).
+		PathParam("id", "{{.orderId}}").
+		JSON(map[string]string{"id": "{{.orderId}}"}).
+		Do()
+	if !put.Status(http.StatusNoContent).Check() {
+		t.Error("interpolated body")
+	}
+
+	var missing string
+	if r := resp.AssertBody().Capture("data.missing", &missing); r.Check() || !strings.HasPrefix(r.Report(), `body: Capture("data.missing")`) {
+		t.Error(r.Report())
+	}
+
+	if _, ok := vars.Get("missing"); ok {
+		t.Error("unknown variable")
+	}
+}
+
+func TestCaptureJSONEscaping(t *testing.T) {
+	vars := assert.NewVars()
+	vars.Set("name", `O"Brien`)
+	vars.Set("path", `C:\tmp`)
+
+	req := assert.Post("http://api.test/users").
+		JSON(map[string]interface{}{"name": "{{.name}}", "tags": []string{"{{.path}}"}, "id": 9007199254740993}).
+		Vars(vars).
+		Build()
+
+	b, _ := io.ReadAll(req.Body)
+
+	var body struct {
+		Name string
+		Tags []string
+		ID   json.Number
+	}
+	if err := json.Unmarshal(b, &body); err != nil {
+		t.Fatal(err, string(b))
+	}
+
+	if body.Name != `O"Brien` || body.Tags[0] != `C:\tmp` || body.ID != "9007199254740993" {
+		t.Error(string(b))
+	}
+}
+
+func TestCapturePanics(t *testing.T) {
+	server := orderServer()
+	defer server.Close()
+
+	resp := assert.Post(server.URL + "/orders").Do()
+
+	for name, fn := range map[string]func(){
+		"non pointer":      func() { resp.AssertBody().Capture("data.id", 1) },
+		"missing variable": func() { assert.Get(server.URL + "/orders/{{.nope}}").Vars(assert.NewVars()).Build() },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error(name)
+				}
+			}()
+
+			fn()
+		}()
+	}
+}
+
+func TestSpecCapture(t *testing.T) {
+	server := orderServer()
+	defer server.Close()
+
+	content := `
+baseURL: {{URL}}
+vars:
+  trace: t-1
+cases:
+  - name: create order
+    request: {method: POST, path: /orders}
+    expect: {status: 201}
+    capture:
+      orderId: data.id
+  - name: get order
+    request:
+      path: /orders/{{.orderId}}
+      query: {trace: "{{.trace}}"}
+      headers: {X-Order: "{{.orderId}}"}
+    expect:
+      status: 200
+      body:
+        - {path: id, value: 42}
+        - {path: id, op: eq, value: "{{.orderId}}"}
+        - {path: id, op: gte, value: "{{ .orderId }}"}
+  - name: update order
+    request:
+      method: PUT
+      path: /orders/{{.orderId}}
+      json: {id: "{{.orderId}}"}
+    expect: {status: 204}
+`
+	path := writeSpec(t, "orders.yaml", content, server.URL)
+	assert.RunSpecFile(t, path)
+
+	spec, err := assert.LoadSpec(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r := spec.Run(spec.Cases[1], http.DefaultClient); r.Passed || !strings.Contains(r.Report, "cannot render template") {
+		t.Error(r)
+	}
+}
@@ -0,0 +1,216 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	assert "github.com/ohmymajo/http-assert"
+)
+
+func TestCassetteRecordReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Session", "live-session")
+		w.Write([]byte(`{"id": 1, "path": "` + r.URL.Path + `", "token": "abc"}`))
+	}))
+
+	for _, name := range []string{"users.json", "users.yaml"} {
+		path := filepath.Join(t.TempDir(), "cassettes", name)
+
+		recorder, err := assert.NewCassette(path, assert.CassetteRecord)
+		if err != nil {
+			t.Fatal(err)
+		}
+		recorder.Redact = append(recorder.Redact, "X-Session", "api_key", "token", "password")
+
+		resp := assert.Post(server.URL+"/users").
+			Client(recorder.Client()).
+			Query("api_key", "secret-key").
+			BearerToken("secret-token").
+			JSON(map[string]string{"name": "ann", "password": "hunter2"}).
+			Do()
+		if !resp.AssertBody().Where("token", "abc").Check() {
+			t.Error(name, "recording should not alter the live response")
+		}
+
+		assert.Get(server.URL + "/users/1").Client(recorder.Client()).Do()
+
+		if err := recorder.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		content, _ := os.ReadFile(path)
+		for _, secret := range []string{"secret-key", "secret-token", "hunter2", "live-session", "abc"} {
+			if strings.Contains(string(content), secret) {
+				t.Errorf("%s: cassette leaks %q", name, secret)
+			}
+		}
+
+		player, err := assert.NewCassette(path, assert.CassetteReplay)
+		if err != nil {
+			t.Fatal(err)
+		}
+		player.Matchers = append(player.Matchers, assert.MatchBody)
+		player.Redact = recorder.Redact
+
+		before := calls
+		client := player.Client()
+
+		r, err := client.Get(server.URL + "/users/1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !assert.New(r).AssertBody().Where("id", 1).Where("path", "/users/1").Check() {
+			t.Error(name, "replayed body")
+		}
+
+		replayed := assert.Post(server.URL+"/users").
+			Client(client).
+			Query("api_key", "other-key").
+			BearerToken("other-token").
+			JSON(map[string]string{"password": "other", "name": "ann"}).
+			Do()
+		if !replayed.Status(http.StatusOK).Check() || !replayed.AssertHeader().Where("X-Session", "[REDACTED]").Check() {
+			t.Error(name, "replayed redacted request")
+		}
+
+		if _, err := client.Get(server.URL + "/users/1"); err == nil {
+			t.Error(name, "interactions should be replayed once")
+		}
+
+		player.Rewind()
+		if _, err := client.Get(server.URL + "/users/1"); err != nil {
+			t.Error(name, err)
+		}
+
+		if _, err := client.Post(server.URL+"/users", "application/json", strings.NewReader(`{"name": "bob"}`)); err == nil {
+			t.Error(name, "body matcher")
+		}
+
+		if calls != before {
+			t.Error(name, "replay should not reach the server")
+		}
+	}
+
+	server.Close()
+
+	if _, err := assert.NewCassette(filepath.Join(t.TempDir(), "missing.json"), assert.CassetteReplay); err == nil {
+		t.Error("missing cassette")
+	}
+
+	if _, err := assert.NewCassette("x.json", "rewind"); err == nil {
+		t.Error("unknown mode")
+	}
+}
+
+func TestCassetteRecordBodyError(t *testing.T) {
+	recorder, err := assert.NewCassette(filepath.Join(t.TempDir(), "broken.json"), assert.CassetteRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder.Base = brokenTransport()
+
+	if _, err := recorder.Client().Get("http://api.test/orders"); err == nil || !strings.Contains(err.Error(), "connection reset") {
+		t.Error(err)
+	}
+
+	if len(recorder.Interactions()) != 0 {
+		t.Error("failed interactions should not be recorded")
+	}
+}
+
+func TestCassetteDefaultRedaction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "sess-live-456"})
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "default.json")
+	recorder, err := assert.NewCassette(path, assert.CassetteRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Get(server.URL+"/me").
+		Client(recorder.Client()).
+		BearerToken("sk-live-123").
+		Header("Proxy-Authorization", "Basic cHJveHk6c2VjcmV0").
+		Header("Cookie", "session=cookie-live-789").
+		Do()
+
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	content, _ := os.ReadFile(path)
+	for _, secret := range []string{"sk-live-123", "cHJveHk6c2VjcmV0", "cookie-live-789", "sess-live-456"} {
+		if strings.Contains(string(content), secret) {
+			t.Errorf("cassette leaks %q", secret)
+		}
+	}
+
+	if !strings.Contains(string(content), `{\"id\": 1}`) {
+		t.Error("bodies without secrets should be stored unchanged")
+	}
+}
+
+func TestCassetteLargeNumbersAndBinaryBodies(t *testing.T) {
+	binary := []byte{0x89, 'P', 'N', 'G', 0xff, 0x00, 0xfe}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/logo.png" {
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(binary)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 9007199254740993, "token": "tok-live-1"}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "numbers.json")
+	recorder, err := assert.NewCassette(path, assert.CassetteRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder.Redact = append(recorder.Redact, "token")
+
+	assert.Get(server.URL + "/me").Client(recorder.Client()).Do()
+	assert.Get(server.URL + "/logo.png").Client(recorder.Client()).Do()
+
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	content, _ := os.ReadFile(path)
+	if !strings.Contains(string(content), "9007199254740993") || strings.Contains(string(content), "tok-live-1") {
+		t.Error(string(content))
+	}
+
+	player, err := assert.NewCassette(path, assert.CassetteReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !assert.Get(server.URL+"/me").Client(player.Client()).Do().AssertBody().Where("id", 9007199254740993).Check() {
+		t.Error("large ids should survive redaction")
+	}
+
+	resp := assert.Get(server.URL + "/logo.png").Client(player.Client()).Do()
+
+	body, _ := io.ReadAll(resp.Resp.Body)
+	if !bytes.Equal(body, binary) {
+		t.Errorf("binary body replayed as %q", body)
+	}
+}
@@ -0,0 +1,129 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	assert "github.com/ohmymajo/http-assert"
+)
+
+func TestAssertWhenThen(t *testing.T) {
+	b := []byte(`{"type": "card", "last4": "4242"}`)
+
+	resp := http.Response{
+		Body: io.NopCloser(bytes.NewReader(b)),
+	}
+
+	http := assert.New(&resp)
+	httpBody := http.AssertBody()
+	val := httpBody.
+		When("type", "card").
+		Then(func(h assert.HttpJson) assert.HttpJson {
+			return h.Has("last4")
+		}).
+		Otherwise(func(h assert.HttpJson) assert.HttpJson {
+			return h.Has("iban")
+		}).
+		Check()
+
+	if !val {
+		t.Fail()
+	}
+}
+
+func TestAssertWhenOtherwiseFail(t *testing.T) {
+	b := []byte(`{"type": "bank", "last4": "4242"}`)
+
+	resp := http.Response{
+		Body: io.NopCloser(bytes.NewReader(b)),
+	}
+
+	http := assert.New(&resp)
+	httpBody := http.AssertBody()
+	val := httpBody.
+		When("type", "card").
+		Then(func(h assert.HttpJson) assert.HttpJson {
+			return h.Has("last4")
+		}).
+		Otherwise(func(h assert.HttpJson) assert.HttpJson {
+			return h.Has("iban")
+		}).
+		Check()
+
+	if val {
+		t.Fail()
+	}
+}
+
+func TestAssertEachWhen(t *testing.T) {
+	b := []byte(`{"methods": [{"type": "card", "last4": "4242"}, {"type": "bank", "iban": "DE89"}]}`)
+
+	resp := http.Response{
+		Body: io.NopCloser(bytes.NewReader(b)),
+	}
+
+	http := assert.New(&resp)
+	httpBody := http.AssertBody()
+	val := httpBody.
+		Each("methods", func(h assert.HttpJson) assert.HttpJson {
+			return h.
+				When("type", "card").
+				Then(func(h assert.HttpJson) assert.HttpJson {
+					return h.WhereType("last4", "string")
+				}).
+				Otherwise(func(h assert.HttpJson) assert.HttpJson {
+					return h.Has("iban")
+				})
+		}).
+		Check()
+
+	if !val {
+		t.Fail()
+	}
+}
+
+func TestAssertEachFail(t *testing.T) {
+	b := []byte(`{"methods": [{"type": "card", "last4": "4242"}, {"type": "card"}]}`)
+
+	resp := http.Response{
+		Body: io.NopCloser(bytes.NewReader(b)),
+	}
+
+	http := assert.New(&resp)
+	httpBody := http.AssertBody()
+	val := httpBody.
+		Each("methods", func(h assert.HttpJson) assert.HttpJson {
+			return h.Has("last4")
+		}).
+		Check()
+
+	if val {
+		t.Fail()
+	}
+}
+
+func TestAssertEachWildcardArrays(t *testing.T) {
+	for _, b := range []string{
+		`{"groups": [{"members": [{"id": 1}, {"id": 2}]}]}`,
+		`{"groups": [{"members": [{"id": 1}, {"id": 2}]}, {"members": [{"id": 3}]}]}`,
+	} {
+		resp := http.Response{
+			Body: io.NopCloser(bytes.NewReader([]byte(b))),
+		}
+
+		var visited []int
+		val := assert.New(&resp).AssertBody().
+			Each("groups.*.members", func(h assert.HttpJson) assert.HttpJson {
+				visited = append(visited, len(h.Body.([]interface{})))
+				return h
+			}).
+			Check()
+
+		if !val || len(visited) != strings.Count(b, `"members"`) {
+			t.Error(b, visited)
+		}
+	}
+}
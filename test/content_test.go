@@ -0,0 +1,99 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+
+	assert "github.com/ohmymajo/http-assert"
+)
+
+func TestAssertContentType(t *testing.T) {
+	header := http.Header{}
+	header.Add("Content-Type", "application/problem+json; Charset=UTF-8; profile=\"https://api.test/p\"")
+
+	resp := http.Response{
+		Header: header,
+	}
+
+	http := assert.New(&resp)
+	cases := []string{
+		"application/problem+json",
+		"application/*+json",
+		"*/*+json",
+		"application/*",
+		"application/problem+json; profile=\"https://api.test/p\"",
+	}
+
+	for _, c := range cases {
+		if !http.AssertContentType(c).Check() {
+			t.Errorf("%s should match", c)
+		}
+	}
+
+	for _, c := range []string{"application/json", "application/json; charset=utf-8", "*/json"} {
+		if http.AssertContentType(c).Check() {
+			t.Errorf("%s should not match a +json subtype", c)
+		}
+	}
+}
+
+func TestAssertContentTypeExactSubtype(t *testing.T) {
+	header := http.Header{}
+	header.Add("Content-Type", "application/json; charset=utf-8")
+
+	resp := http.Response{
+		Header: header,
+	}
+
+	http := assert.New(&resp)
+	if !http.AssertContentType("application/json").Check() {
+		t.Error("exact subtype should match")
+	}
+
+	if http.AssertContentType("application/*+json").Check() {
+		t.Error("suffix pattern should not match a subtype without suffix")
+	}
+}
+
+func TestAssertContentTypeFail(t *testing.T) {
+	header := http.Header{}
+	header.Add("Content-Type", "text/html; charset=iso-8859-1")
+
+	resp := http.Response{
+		Header: header,
+	}
+
+	http := assert.New(&resp)
+	cases := []string{
+		"application/json",
+		"text/plain",
+		"text/html; charset=utf-8",
+	}
+
+	for _, c := range cases {
+		if http.AssertContentType(c).Check() {
+			t.Errorf("%s should not match", c)
+		}
+	}
+}
+
+func TestAssertBodyRejectsNonJSON(t *testing.T) {
+	header := http.Header{}
+	header.Add("Content-Type", "text/html")
+
+	resp := http.Response{
+		Header: header,
+		Body:   io.NopCloser(bytes.NewReader([]byte(`{"message": "Hello World"}`))),
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fail()
+		}
+	}()
+
+	http := assert.New(&resp)
+	http.AssertBody()
+}
@@ -0,0 +1,62 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	assert "github.com/ohmymajo/http-assert"
+)
+
+func TestAssertCookies(t *testing.T) {
+	header := http.Header{}
+	header.Add("Set-Cookie", "theme=dark; Path=/settings; Domain=example.com; Max-Age=86400")
+	header.Add("Set-Cookie", "session=abc123; Path=/; Secure; HttpOnly; SameSite=Strict; Partitioned")
+
+	resp := http.Response{
+		Header: header,
+	}
+
+	http := assert.New(&resp)
+	val := http.AssertCookies().
+		HasAll([]string{"theme", "session"}).
+		Where("session", "abc123").
+		WhereNot("theme", "light").
+		CookiePath("theme", "/settings").
+		CookieDomain("theme", "example.com").
+		CookieMaxAgeAtLeast("theme", time.Hour).
+		CookieExpiresAfter("theme", time.Now()).
+		CookieSecure("session").
+		CookieHttpOnly("session").
+		CookieSameSite("session", "strict").
+		CookiePartitioned("session").
+		SecureSessionCookie("session").
+		Check()
+
+	if !val {
+		t.Fail()
+	}
+}
+
+func TestAssertCookiesFail(t *testing.T) {
+	header := http.Header{}
+	header.Add("Set-Cookie", "theme=dark")
+	header.Add("Set-Cookie", "session=abc123; Path=/; HttpOnly")
+
+	resp := http.Response{
+		Header: header,
+	}
+
+	http := assert.New(&resp)
+	if !http.AssertCookies().Has("session").Check() {
+		t.Fail()
+	}
+
+	if http.AssertCookies().SecureSessionCookie("session").Check() {
+		t.Fail()
+	}
+
+	if http.AssertCookies().Has("missing").Check() {
+		t.Fail()
+	}
+}
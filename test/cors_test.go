@@ -0,0 +1,99 @@
+package test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	assert "github.com/ohmymajo/http-assert"
+)
+
+func TestAssertPreflight(t *testing.T) {
+	header := http.Header{}
+	header.Add("Access-Control-Allow-Origin", "https://app.test")
+	header.Add("Access-Control-Allow-Credentials", "true")
+	header.Add("Access-Control-Allow-Methods", "GET, PUT, DELETE")
+	header.Add("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	header.Add("Access-Control-Max-Age", "600")
+	header.Add("Vary", "Origin")
+
+	resp := http.Response{
+		StatusCode: 204,
+		Header:     header,
+	}
+
+	http := assert.New(&resp)
+	val := http.AssertPreflight(assert.CORSRequest{
+		Origin:      "https://app.test",
+		Method:      "PUT",
+		Headers:     []string{"content-type", "authorization"},
+		Credentials: true,
+	}).CORSMaxAgeAtLeast(5 * time.Minute)
+
+	if !val.Check() {
+		t.Error(val.Report())
+	}
+}
+
+func TestAssertPreflightFail(t *testing.T) {
+	header := http.Header{}
+	header.Add("Access-Control-Allow-Origin", "*")
+	header.Add("Access-Control-Allow-Methods", "GET")
+	header.Add("Access-Control-Allow-Headers", "*")
+
+	resp := http.Response{
+		StatusCode: 200,
+		Header:     header,
+	}
+
+	http := assert.New(&resp)
+	val := http.AssertPreflight(assert.CORSRequest{
+		Origin:      "https://app.test",
+		Method:      "DELETE",
+		Headers:     []string{"Authorization"},
+		Credentials: true,
+	})
+
+	if val.Check() {
+		t.Fail()
+	}
+
+	report := val.Report()
+	expected := []string{
+		"cannot be * for credentialed requests",
+		"Access-Control-Allow-Credentials must be true",
+		"Access-Control-Allow-Methods must allow DELETE",
+		"Access-Control-Allow-Headers must allow Authorization",
+	}
+	for _, e := range expected {
+		if !strings.Contains(report, e) {
+			t.Errorf("report is missing %q:\n%s", e, report)
+		}
+	}
+}
+
+func TestAssertCORS(t *testing.T) {
+	header := http.Header{}
+	header.Add("Access-Control-Allow-Origin", "https://app.test")
+	header.Add("Access-Control-Expose-Headers", "X-Request-Id, ETag")
+
+	resp := http.Response{
+		StatusCode: 200,
+		Header:     header,
+	}
+
+	http := assert.New(&resp)
+	val := http.AssertCORS(assert.CORSRequest{Origin: "https://app.test"})
+
+	if val.Check() || !strings.Contains(val.Report(), "Vary must include Origin") {
+		t.Error(val.Report())
+	}
+
+	header.Add("Vary", "Accept-Encoding, Origin")
+	val = http.AssertCORS(assert.CORSRequest{Origin: "https://app.test"}).ExposesHeader("etag")
+
+	if !val.Check() {
+		t.Error(val.Report())
+	}
+}
@@ -0,0 +1,133 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	assert "github.com/ohmymajo/http-assert"
+	"github.com/ohmymajo/http-assert/pkg/expr"
+)
+
+func evalExpr(t *testing.T, src string, body string) (bool, error) {
+	var data interface{}
+	d := json.NewDecoder(bytes.NewReader([]byte(body)))
+	d.UseNumber()
+	d.Decode(&data)
+
+	program, err := expr.Compile(src, map[string]string{"body": "dyn", "header": "map"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return program.Eval(map[string]interface{}{"body": data, "header": http.Header{}})
+}
+
+func TestExprEval(t *testing.T) {
+	body := `{"items": [{"price": 10, "tags": ["a"]}, {"price": 2.5, "tags": []}], "meta": {"total": 2, "name": "list"}}`
+
+	valid := []string{
+		`body.items.all(i, i.price > 0)`,
+		`body.items.exists(i, i.price == 2.5)`,
+		`body.items.exists_one(i, size(i.tags) == 1)`,
+		`body.meta.total == body.items.size()`,
+		`body.items[0].price + body.items[1].price == 12.5`,
+		`body.meta["name"].startsWith("li") && !body.meta.name.endsWith("x")`,
+		`has(body.meta.total) && !has(body.meta.missing)`,
+		`"total" in body.meta && 10 in body.items.map(i, i.price)`,
+		`body.items.filter(i, i.price > 5).size() == 1`,
+		`(body.meta.total > 1 ? "many" : "one") == "many"`,
+		`body.meta.name.matches("^l.*t$") && 7 % 4 == 3`,
+	}
+
+	for _, src := range valid {
+		ok, err := evalExpr(t, src, body)
+		if err != nil || !ok {
+			t.Errorf("%s: %v %v", src, ok, err)
+		}
+	}
+}
+
+func TestExprCompileError(t *testing.T) {
+	invalid := []string{
+		`body.items.all(i, i.price > 0`,
+		`unknown.value == 1`,
+		`body.name.nope()`,
+		`"a" < 1`,
+		`1 && true`,
+		`1 + 2`,
+		`size("a", "b") == 1`,
+	}
+
+	for _, src := range invalid {
+		_, err := expr.Compile(src, map[string]string{"body": "dyn", "header": "map"})
+		if err == nil {
+			t.Errorf("%s: expected compile error", src)
+		}
+	}
+}
+
+func TestExprEvalError(t *testing.T) {
+	_, err := evalExpr(t, `body.name < 1`, `{"name": "a"}`)
+	if err == nil {
+		t.Fail()
+	}
+}
+
+func TestAssertExpect(t *testing.T) {
+	b := []byte(`{"items": [{"price": 10}, {"price": 2.5}]}`)
+
+	header := http.Header{}
+	header.Add("Content-Type", "application/json; charset=utf-8")
+
+	resp := http.Response{
+		Header: header,
+		Body:   io.NopCloser(bytes.NewReader(b)),
+	}
+
+	http := assert.New(&resp)
+	httpBody := http.AssertBody()
+	val := httpBody.
+		Expect(`body.items.all(i, i.price > 0) && header["Content-Type"].startsWith("application/json")`).
+		Check()
+
+	if !val {
+		t.Fail()
+	}
+}
+
+func TestAssertExpectFail(t *testing.T) {
+	b := []byte(`{"items": [{"price": 10}, {"price": 0}]}`)
+
+	resp := http.Response{
+		Body: io.NopCloser(bytes.NewReader(b)),
+	}
+
+	http := assert.New(&resp)
+	httpBody := http.AssertBody()
+	val := httpBody.
+		Expect(`body.items.all(i, i.price > 0)`).
+		Check()
+
+	if val {
+		t.Fail()
+	}
+}
+
+func TestAssertExpectEvalError(t *testing.T) {
+	b := []byte(`{"items": [{"price": 10}]}`)
+
+	resp := http.Response{
+		Body: io.NopCloser(bytes.NewReader(b)),
+	}
+
+	http := assert.New(&resp)
+	val := http.AssertBody().Expect(`body.items[3].price > 0`)
+
+	if val.Check() || !strings.HasPrefix(val.Report(), `body: Expect("body.items[3].price > 0"): eval`) {
+		t.Error(val.Report())
+	}
+}
@@ -48,3 +48,62 @@ func TestFindObjectWithArrayObject(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestFindObjectLeaf(t *testing.T) {
+	j := []byte(`{"data": { "user": { "name": "Ann" } }}`)
+
+	var data interface{}
+	json.Unmarshal(j, &data)
+
+	val, ok := filter.Find("data.user", data).(map[string]interface{})
+	if !ok || val["name"] != "Ann" {
+		t.Fail()
+	}
+}
+
+func TestFindNestedArrays(t *testing.T) {
+	j := []byte(`{"data": { "grid": [[1, 2], [3, 4]] }}`)
+
+	var data interface{}
+	json.Unmarshal(j, &data)
+
+	val := filter.Find("data.grid.1.0", data)
+	valid := validation.EqualValue(val, 3, "int")
+
+	if !valid {
+		t.Fail()
+	}
+}
+
+func TestFindMissing(t *testing.T) {
+	j := []byte(`{"data": { "options": [1, 2, 3], "message": "Hello World" }}`)
+
+	var data interface{}
+	json.Unmarshal(j, &data)
+
+	for _, cursor := range []string{
+		"data.missing",
+		"data.missing.value",
+		"data.options.3",
+		"data.options.-1",
+		"data.message.value",
+		"data.options.0.value",
+	} {
+		if val := filter.Find(cursor, data); val != nil {
+			t.Errorf("%s: %v", cursor, val)
+		}
+	}
+}
+
+func TestFindAllWildcard(t *testing.T) {
+	j := []byte(`{"data": { "items": [{ "value": 1 }, { "value": 2 }, { "other": 3 }] }}`)
+
+	var data interface{}
+	json.Unmarshal(j, &data)
+
+	val := filter.FindAll("data.items.*.value", data)
+
+	if len(val) != 2 {
+		t.Fail()
+	}
+}
@@ -0,0 +1,84 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	assert "github.com/ohmymajo/http-assert"
+)
+
+func usersHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/users/")
+		if id == "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":     id,
+			"method": r.Method,
+			"trace":  r.Header.Get("X-Trace"),
+			"body":   string(body),
+		})
+	})
+
+	return mux
+}
+
+func withHeader(name, value string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Header.Set(name, r.Header.Get(name)+value)
+			w.Header().Set(name, r.Header.Get(name))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	h := assert.Handler(usersHandler(), withHeader("X-Trace", "a"), withHeader("X-Trace", "b"))
+
+	val := h.Get("/users/{id}").
+		PathParam("id", "42").
+		Do().
+		IsSuccess().
+		Where("X-Trace", "ab").
+		AssertBody().
+		Where("id", "42").
+		Where("method", "GET").
+		Where("trace", "ab")
+
+	if !val.Check() {
+		t.Error(val.Report())
+	}
+
+	val = h.Post("/users/7").
+		JSON(map[string]interface{}{"name": "majo"}).
+		Do().
+		AssertBody().
+		Where("body", `{"name":"majo"}`)
+
+	if !val.Check() {
+		t.Error(val.Report())
+	}
+}
+
+func TestHandlerFail(t *testing.T) {
+	val := assert.Handler(usersHandler()).
+		Get("/users/").
+		Do().
+		IsSuccess()
+
+	if val.Check() || !strings.Contains(val.Report(), "got 404 Not Found") || !strings.Contains(val.Report(), "request: GET /users/") {
+		t.Error(val.Report())
+	}
+}
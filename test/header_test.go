@@ -0,0 +1,164 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	assert "github.com/ohmymajo/http-assert"
+)
+
+func TestAssertHeaderNumeric(t *testing.T) {
+	header := http.Header{}
+	header.Add("Content-Length", "5120")
+	header.Add("X-RateLimit-Remaining", "12")
+	header.Add("X-Load", "0.75")
+	header.Add("Age", "30")
+
+	resp := http.Response{
+		Header: header,
+	}
+
+	http := assert.New(&resp)
+	val := http.AssertHeader().
+		WhereLte("Content-Length", 1048576).
+		WhereGte("X-RateLimit-Remaining", 1).
+		WhereLt("X-Load", 0.8).
+		WhereGt("X-Load", 0).
+		WhereLt("Age", time.Minute).
+		Check()
+
+	if !val {
+		t.Fail()
+	}
+}
+
+func TestAssertHeaderNumericFail(t *testing.T) {
+	header := http.Header{}
+	header.Add("X-RateLimit-Remaining", "0")
+
+	resp := http.Response{
+		Header: header,
+	}
+
+	http := assert.New(&resp)
+	val := http.AssertHeader().
+		WhereGte("X-RateLimit-Remaining", 1).
+		Check()
+
+	if val {
+		t.Fail()
+	}
+}
+
+func TestAssertHeaderNumericParseError(t *testing.T) {
+	header := http.Header{}
+	header.Add("Content-Length", "large")
+	header.Add("Retry-After", "soon")
+
+	resp := http.Response{
+		Header: header,
+	}
+
+	http := assert.New(&resp)
+	val := http.AssertHeader().
+		Soft().
+		WhereLte("Content-Length", 1048576).
+		WhereGt("Retry-After", 30*time.Second).
+		WhereGt("Content-Length", "1").
+		WhereGt("X-Missing", 1)
+
+	expected := []string{
+		`header: WhereLte("Content-Length", 1048576): cannot parse header Content-Length value "large" as number`,
+		`header: WhereGt("Retry-After", 30000000000): cannot parse header Retry-After value "soon" as duration`,
+		`header: WhereGt("Content-Length", "1"): cannot compare header Content-Length with string value`,
+		`header: WhereGt("X-Missing", 1)`,
+	}
+
+	if val.Check() || strings.Join(val.Failures, "\n") != strings.Join(expected, "\n") {
+		t.Error(val.Report())
+	}
+}
+
+func TestAssertHeaderMultiValue(t *testing.T) {
+	header := http.Header{}
+	header.Add("Vary", "Accept-Encoding")
+	header.Add("Vary", "Origin, Accept")
+	header.Add("Set-Cookie", "a=1; Expires=Wed, 21 Oct 2015 07:28:00 GMT")
+	header.Add("Set-Cookie", "b=2")
+	header.Add("Link", `<https://api.test/items?page=2>; rel="next", <https://api.test/items?page=5>; rel="last"`)
+
+	resp := http.Response{
+		Header: header,
+	}
+
+	http := assert.New(&resp)
+	httpHeader := http.AssertHeader()
+	val := httpHeader.
+		HasHeaderValue("Vary", "Origin").
+		HasHeaderValue("Set-Cookie", "b=2").
+		HasHeaderToken("vary", "accept").
+		HasHeaderToken("Link", "<https://api.test/items?page=5>").
+		HeaderCount("Vary", 3).
+		HeaderCount("Set-Cookie", 2).
+		HeaderCount("Link", 2).
+		Check()
+
+	if !val {
+		t.Fail()
+	}
+
+	if len(httpHeader.HeaderValues("Vary")) != 2 {
+		t.Fail()
+	}
+}
+
+func TestAssertHeaderMultiValueFail(t *testing.T) {
+	header := http.Header{}
+	header.Add("Vary", "Accept-Encoding, Accept")
+
+	resp := http.Response{
+		Header: header,
+	}
+
+	http := assert.New(&resp)
+	val := http.AssertHeader().
+		HasHeaderToken("Vary", "Origin").
+		Check()
+
+	if val {
+		t.Fail()
+	}
+}
+
+func TestAssertComparisonOrder(t *testing.T) {
+	header := http.Header{}
+	header.Add("Content-Type", "application/json")
+	header.Add("X-N", "5")
+
+	newResp := func() *http.Response {
+		return &http.Response{
+			Header: header,
+			Body:   io.NopCloser(bytes.NewReader([]byte(`{"n": 5}`))),
+		}
+	}
+
+	h := assert.New(newResp())
+	for name, ok := range map[string]bool{
+		"header WhereGt":  h.AssertHeader().WhereGt("X-N", 3).WhereGte("X-N", 5).WhereLt("X-N", 7).WhereLte("X-N", 5).Check(),
+		"body WhereGt":    h.AssertBody().WhereGt("n", 3).WhereGte("n", 5).WhereLt("n", 7).WhereLte("n", 5).Check(),
+		"header Compare":  h.AssertHeader().Compare("X-N", "gt", 3).Check(),
+		"body Compare":    h.AssertBody().Compare("n", "gt", 3).Check(),
+		"That header Gt":  assert.That(newResp()).Header("X-N").Gt(3).Verify(),
+		"That body Gt":    assert.That(newResp()).Body("n").Gt(3).Verify(),
+		"header inverted": !h.AssertHeader().WhereGt("X-N", 7).Check(),
+		"body inverted":   !h.AssertBody().WhereGt("n", 7).Check(),
+	} {
+		if !ok {
+			t.Error(name)
+		}
+	}
+}
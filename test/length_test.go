@@ -0,0 +1,79 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+
+	assert "github.com/ohmymajo/http-assert"
+)
+
+func TestAssertHasLengthTypes(t *testing.T) {
+	b := []byte(`{"name": "héllo", "obj": {"a": 1, "b": 2}, "nested": {"arr": [1, 2]}, "items": [{"id": 1}, {"id": 2}, {}]}`)
+
+	resp := http.Response{
+		Body: io.NopCloser(bytes.NewReader(b)),
+	}
+
+	http := assert.New(&resp)
+	httpBody := http.AssertBody()
+	val := httpBody.
+		HasLength("name", 5).
+		HasLength("obj", 2).
+		HasLength("nested.arr", 2).
+		HasLength("items.*.id", 2).
+		HasLengthGte("items", 3).
+		HasLengthLte("obj", 2).
+		HasLengthBetween("name", 1, 10).
+		NotEmpty("nested").
+		Check()
+
+	if !val {
+		t.Fail()
+	}
+}
+
+func TestAssertHasLengthFail(t *testing.T) {
+	b := []byte(`{"count": 3, "arr": []}`)
+
+	resp := http.Response{
+		Body: io.NopCloser(bytes.NewReader(b)),
+	}
+
+	http := assert.New(&resp)
+	httpBody := http.AssertBody()
+	val := httpBody.
+		IsEmpty("arr").
+		HasLength("count", 3).
+		Check()
+
+	if val {
+		t.Fail()
+	}
+}
+
+func TestAssertHeaderLength(t *testing.T) {
+	header := http.Header{}
+	header.Add("x-request-id", "abcdef")
+	header.Add("x-empty", "")
+
+	resp := http.Response{
+		Header: header,
+	}
+
+	http := assert.New(&resp)
+	val := http.AssertHeader().
+		HasLength("x-request-id", 6).
+		HasLengthBetween("x-request-id", 1, 8).
+		IsEmpty("x-empty").
+		Check()
+
+	if !val {
+		t.Fail()
+	}
+
+	if http.AssertHeader().IsEmpty("x-missing").Check() {
+		t.Error("absent header should not be empty")
+	}
+}
@@ -0,0 +1,35 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/ohmymajo/http-assert/pkg/header"
+)
+
+func TestSplitList(t *testing.T) {
+	val := header.SplitList([]string{`a, "b, c"`, `<https://x.test/?a=1,2>; rel="next", d`})
+
+	if len(val) != 4 || val[1] != `"b, c"` || val[3] != "d" {
+		t.Error(val)
+	}
+}
+
+func TestParseDirectives(t *testing.T) {
+	val := header.ParseDirectives([]string{`Max-Age=60, private="Set-Cookie"`, "no-cache"})
+
+	if val["max-age"] != "60" || val["private"] != "Set-Cookie" {
+		t.Error(val)
+	}
+
+	if _, ok := val["no-cache"]; !ok {
+		t.Error(val)
+	}
+}
+
+func TestParseCSP(t *testing.T) {
+	val := header.ParseCSP([]string{"default-src 'self'; Script-Src 'self' https://cdn.test", "img-src *"})
+
+	if len(val) != 2 || len(val[0]["script-src"]) != 2 || val[1]["img-src"][0] != "*" {
+		t.Error(val)
+	}
+}
@@ -0,0 +1,80 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	assert "github.com/ohmymajo/http-assert"
+)
+
+func TestAssertProblem(t *testing.T) {
+	b := []byte(`{"type": "https://api.test/problems/not-found", "title": "Not Found", "status": 404, "detail": "order 42 does not exist", "instance": "/orders/42", "orderId": 42}`)
+
+	header := http.Header{}
+	header.Add("Content-Type", "application/problem+json")
+
+	resp := http.Response{
+		StatusCode: 404,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader(b)),
+	}
+
+	http := assert.New(&resp)
+	val := http.AssertProblem().
+		WithType("https://api.test/problems/not-found").
+		WithTitle("Not Found").
+		WithStatus(404).
+		WithDetail("order 42 does not exist").
+		WithInstance("/orders/42").
+		Where("orderId", 42)
+
+	if !val.Check() {
+		t.Error(val.Report())
+	}
+}
+
+func TestAssertProblemFail(t *testing.T) {
+	b := []byte(`{"type": "about:blank", "status": 400}`)
+
+	header := http.Header{}
+	header.Add("Content-Type", "application/problem+json")
+
+	resp := http.Response{
+		StatusCode: 422,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader(b)),
+	}
+
+	http := assert.New(&resp)
+	val := http.AssertProblem()
+
+	if val.Check() {
+		t.Fail()
+	}
+
+	report := val.Report()
+	if !strings.Contains(report, `"title" is missing`) || !strings.Contains(report, `"status" is 400, response status is 422`) {
+		t.Error(report)
+	}
+}
+
+func TestAssertProblemContentType(t *testing.T) {
+	header := http.Header{}
+	header.Add("Content-Type", "text/html")
+
+	resp := http.Response{
+		StatusCode: 500,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader([]byte(`<h1>Error</h1>`))),
+	}
+
+	http := assert.New(&resp)
+	val := http.AssertProblem().WithStatus(500)
+
+	if val.Check() || !strings.Contains(val.Report(), "application/problem+json") {
+		t.Error(val.Report())
+	}
+}
@@ -0,0 +1,123 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	assert "github.com/ohmymajo/http-assert"
+)
+
+func echoServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		var files []string
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			r.Body = io.NopCloser(strings.NewReader(string(body)))
+			r.ParseMultipartForm(1 << 20)
+			for field := range r.MultipartForm.File {
+				files = append(files, field)
+			}
+		}
+
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"method":        r.Method,
+			"query":         r.URL.Query().Get("page"),
+			"authorization": r.Header.Get("Authorization"),
+			"contentType":   r.Header.Get("Content-Type"),
+			"body":          string(body),
+			"files":         files,
+		})
+	}))
+}
+
+func TestRequestBuilder(t *testing.T) {
+	server := echoServer()
+	defer server.Close()
+
+	val := assert.Post(server.URL+"/orders").
+		Query("page", "2").
+		BearerToken("secret").
+		JSON(map[string]interface{}{"id": 1}).
+		Timeout(time.Second).
+		Do().
+		IsSuccess().
+		AssertBody().
+		Where("method", "POST").
+		Where("query", "2").
+		Where("authorization", "Bearer secret").
+		Where("contentType", "application/json").
+		Where("body", `{"id":1}`)
+
+	if !val.Check() {
+		t.Error(val.Report())
+	}
+}
+
+func TestRequestBuilderForm(t *testing.T) {
+	server := echoServer()
+	defer server.Close()
+
+	val := assert.Request(http.MethodPut, server.URL).
+		BasicAuth("user", "pass").
+		Form(url.Values{"name": {"majo"}}).
+		Do().
+		AssertBody().
+		Where("authorization", "Basic dXNlcjpwYXNz").
+		Where("body", "name=majo")
+
+	if !val.Check() {
+		t.Error(val.Report())
+	}
+
+	val = assert.Post(server.URL).
+		Field("name", "majo").
+		File("avatar", "avatar.png", []byte("png")).
+		Do().
+		AssertBody().
+		Where("files.0", "avatar")
+
+	if !val.Check() {
+		t.Error(val.Report())
+	}
+}
+
+func TestRequestBuilderReport(t *testing.T) {
+	server := echoServer()
+	defer server.Close()
+
+	val := assert.Post(server.URL+"/orders").
+		JSON(map[string]interface{}{"id": 1}).
+		Do().
+		AssertBody().
+		Where("method", "GET")
+
+	report := val.Report()
+	if !strings.Contains(report, "request: POST "+server.URL+"/orders") || !strings.Contains(report, `body: {"id":1}`) {
+		t.Error(report)
+	}
+}
+
+func TestRequestBuilderTimeout(t *testing.T) {
+	server := echoServer()
+	defer server.Close()
+
+	defer func() {
+		if recover() == nil {
+			t.Fail()
+		}
+	}()
+
+	assert.Get(server.URL + "/slow").Timeout(10 * time.Millisecond).Do()
+}
@@ -0,0 +1,251 @@
+package test
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	assert "github.com/ohmymajo/http-assert"
+)
+
+func TestStubRules(t *testing.T) {
+	server := assert.NewStubServer()
+	defer server.Close()
+
+	server.On("GET", "/users/{id}").
+		WithQuery("expand", "true").
+		WithHeader("X-Api-Key", "secret").
+		ReplyJSON(map[string]interface{}{"expanded": true}).
+		ReplyHeader("X-User", "{{.Params.id}}")
+	server.On("GET", "/users/{id}").
+		ReplyHeader("Content-Type", "application/json").
+		ReplyBody(`{"id": "{{.Params.id}}", "page": "{{.Query.page}}"}`)
+	server.On("POST", "/orders").
+		WithBody(func(h assert.HttpJson) assert.HttpJson { return h.Where("kind", "express") }).
+		Reply(http.StatusCreated).
+		Delay(50 * time.Millisecond)
+	server.On("POST", "/orders").Reply(http.StatusAccepted)
+
+	resp := assert.Get(server.URL+"/users/7").Query("expand", "true").Header("X-Api-Key", "secret").Do()
+	if !resp.AssertBody().Where("expanded", true).Check() || !resp.AssertHeader().Where("X-User", "7").Check() {
+		t.Error("first rule")
+	}
+
+	if !assert.Get(server.URL+"/users/9").Query("page", "2").Do().AssertBody().Where("id", "9").Where("page", "2").Check() {
+		t.Error("templated body")
+	}
+
+	start := time.Now()
+	if !assert.Post(server.URL + "/orders").JSON(map[string]string{"kind": "express"}).Do().Status(http.StatusCreated).Check() {
+		t.Error("body predicate")
+	}
+	if time.Since(start) < 50*time.Millisecond {
+		t.Error("delay")
+	}
+
+	if !assert.Post(server.URL + "/orders").JSON(map[string]string{"kind": "standard"}).Do().Status(http.StatusAccepted).Check() {
+		t.Error("body mismatch should fall through")
+	}
+
+	if !assert.Post(server.URL + "/orders").Do().Status(http.StatusAccepted).Check() {
+		t.Error("empty body should fall through")
+	}
+
+	if !assert.Delete(server.URL + "/users/7").Do().Status(http.StatusNotFound).Check() {
+		t.Error("unmatched request")
+	}
+
+	if len(server.Requests()) != 6 {
+		t.Error("requests should still be recorded")
+	}
+}
+
+func TestStubRulesFault(t *testing.T) {
+	server := assert.NewStubServer()
+	defer server.Close()
+
+	server.On("GET", "/close").Fault("close")
+	server.On("GET", "/malformed").Fault("malformed")
+
+	for _, path := range []string{"/close", "/malformed"} {
+		if resp, err := http.Get(server.URL + path); err == nil {
+			resp.Body.Close()
+			t.Errorf("%s should fail", path)
+		}
+	}
+}
+
+func TestStubRulesLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"rules.json": `[
+			{"method": "GET", "path": "/items/{id}", "query": {"v": "2"},
+			 "reply": {"status": 200, "json": {"id": 1, "tags": ["a"]}}},
+			{"method": "POST", "path": "/items", "body": {"name": "pen"},
+			 "reply": {"status": 201, "headers": {"Location": "/items/{{.Body.name}}"}, "delay": "10ms"}}
+		]`,
+		"rules.yaml": `
+- method: GET
+  path: /items/{id}
+  query:
+    v: "2"
+  reply:
+    status: 200
+    json:
+      id: 1
+      tags: [a]
+- method: POST
+  path: /items
+  body:
+    name: pen
+  reply:
+    status: 201
+    headers:
+      Location: /items/{{.Body.name}}
+    delay: 10ms
+`,
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		server := assert.NewStubServer()
+		if err := server.LoadRules(path); err != nil {
+			t.Fatal(name, err)
+		}
+
+		if !assert.Get(server.URL+"/items/1").Query("v", "2").Do().AssertBody().Where("id", 1).HasLength("tags", 1).Check() {
+			t.Error(name, "json reply")
+		}
+
+		resp := assert.Post(server.URL + "/items").JSON(map[string]string{"name": "pen"}).Do()
+		if !resp.Status(http.StatusCreated).Check() || !resp.AssertHeader().Where("Location", "/items/pen").Check() {
+			t.Error(name, "body rule")
+		}
+
+		if !assert.Get(server.URL + "/items/1").Do().Status(http.StatusNotFound).Check() {
+			t.Error(name, "query mismatch")
+		}
+
+		server.Close()
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	os.WriteFile(bad, []byte(`{`), 0o644)
+	server := assert.NewStubServer()
+	defer server.Close()
+
+	if err := server.LoadRules(bad); err == nil {
+		t.Error("invalid rules should fail")
+	}
+}
+
+func TestStubRulesStatusTemplate(t *testing.T) {
+	server := assert.NewStubServer()
+	defer server.Close()
+
+	server.On("GET", "/users/{id}").ReplyStatus(`{{if eq .Params.id "0"}}404{{else}}200{{end}}`)
+	server.On("GET", "/broken").ReplyStatus(`{{.Query.code}}`)
+
+	if !assert.Get(server.URL + "/users/0").Do().Status(http.StatusNotFound).Check() {
+		t.Error("templated 404")
+	}
+
+	if !assert.Get(server.URL + "/users/1").Do().Status(http.StatusOK).Check() {
+		t.Error("templated 200")
+	}
+
+	if !assert.Get(server.URL+"/broken").Query("code", "teapot").Do().Status(http.StatusInternalServerError).Check() {
+		t.Error("invalid templated status")
+	}
+
+	path := filepath.Join(t.TempDir(), "status.yaml")
+	os.WriteFile(path, []byte(`
+- method: GET
+  path: /items
+  reply:
+    status: "{{if .Query.missing}}404{{else}}200{{end}}"
+`), 0o644)
+
+	if err := server.LoadRules(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if !assert.Get(server.URL+"/items").Query("missing", "1").Do().Status(http.StatusNotFound).Check() {
+		t.Error("templated status from file")
+	}
+}
+
+func TestStubRulesUnknownFault(t *testing.T) {
+	server := assert.NewStubServer()
+	defer server.Close()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("unknown fault should panic")
+			}
+		}()
+
+		server.On("GET", "/y").Fault("clsoe")
+	}()
+
+	path := filepath.Join(t.TempDir(), "fault.json")
+	os.WriteFile(path, []byte(`[{"method": "GET", "path": "/x", "reply": {"fault": "reset"}}]`), 0o644)
+
+	if err := server.LoadRules(path); err == nil || !strings.Contains(err.Error(), `unknown fault "reset"`) {
+		t.Error(err)
+	}
+
+	if !assert.Get(server.URL + "/x").Do().Status(http.StatusNotFound).Check() {
+		t.Error("rules from an invalid file should not be registered")
+	}
+}
+
+func TestStubRulesTemplateErrors(t *testing.T) {
+	server := assert.NewStubServer()
+	defer server.Close()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("unknown template field should panic")
+			}
+		}()
+
+		server.On("GET", "/y").ReplyBody(`{{.Nope}}`)
+	}()
+
+	for _, rules := range []string{
+		`[{"method": "GET", "path": "/x", "reply": {"body": "{{.Nope}}"}}]`,
+		`[{"method": "GET", "path": "/x", "reply": {"headers": {"X-Id": "{{.Params.id"}}}]`,
+		`[{"method": "GET", "path": "/x", "reply": {"json": {"id": "{{.Nope.id}}"}}}]`,
+	} {
+		path := filepath.Join(t.TempDir(), "templates.json")
+		os.WriteFile(path, []byte(rules), 0o644)
+
+		if err := server.LoadRules(path); err == nil || !strings.Contains(err.Error(), "cannot parse stub template") {
+			t.Error(rules, err)
+		}
+	}
+
+	server.On("POST", "/items").ReplyBody(`{{range .Body.items}}{{.name}}{{end}} {{index .Body.items 5}}`)
+
+	val := assert.Post(server.URL + "/items").
+		JSON(map[string]interface{}{"items": []map[string]string{{"name": "a"}}}).
+		Do().
+		Status(http.StatusInternalServerError)
+
+	body, _ := io.ReadAll(val.Resp.Body)
+	if !val.Check() || !strings.Contains(string(body), "cannot render stub template") {
+		t.Error(val.Report(), string(body))
+	}
+}
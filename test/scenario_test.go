@@ -0,0 +1,152 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	assert "github.com/ohmymajo/http-assert"
+)
+
+type fakeT struct {
+	cleanups []func()
+	errors   []string
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Cleanup(fn func()) {
+	f.cleanups = append(f.cleanups, fn)
+}
+
+func (f *fakeT) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeT) finish() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+func TestScenario(t *testing.T) {
+	server := orderServer()
+	t.Cleanup(server.Close)
+
+	server.On("DELETE", "/orders/{id}").Reply(http.StatusNoContent)
+
+	var deleted bool
+	t.Cleanup(func() {
+		if !deleted {
+			t.Error("cleanup step should run")
+		}
+	})
+
+	assert.Scenario(t).
+		Step("create", func(ctx *assert.ScenarioContext) assert.HttpJson {
+			ctx.Set("trace", "t-1")
+			return assert.Post(server.URL+"/orders").Do().
+				Status(http.StatusCreated).
+				AssertBody().
+				CaptureAs(ctx.Vars, "orderId", "data.id")
+		}).
+		Cleanup("delete", func(ctx *assert.ScenarioContext) assert.HttpJson {
+			deleted = true
+			return assert.Delete(server.URL + "/orders/{{.orderId}}").Vars(ctx.Vars).Do().Status(http.StatusNoContent)
+		}).
+		Step("fetch", func(ctx *assert.ScenarioContext) assert.HttpJson {
+			return assert.Get(server.URL+"/orders/{{.orderId}}").
+				Vars(ctx.Vars).
+				Query("trace", "{{.trace}}").
+				Header("X-Order", "{{.orderId}}").
+				Do().
+				AssertBody().
+				Where("id", 42)
+		}, assert.DependsOn("create"))
+}
+
+func TestScenarioFailures(t *testing.T) {
+	server := orderServer()
+	defer server.Close()
+
+	ft := &fakeT{}
+	attempts := 0
+	var cleaned []string
+
+	s := assert.Scenario(ft).
+		Step("create", func(ctx *assert.ScenarioContext) assert.HttpJson {
+			return assert.Post(server.URL+"/orders").Do().Status(http.StatusCreated).AssertBody().CaptureAs(ctx.Vars, "orderId", "data.id")
+		}).
+		Cleanup("first", func(ctx *assert.ScenarioContext) assert.HttpJson {
+			cleaned = append(cleaned, "first")
+			return assert.Post(server.URL + "/orders").Do().IsSuccess()
+		}).
+		Step("flaky", func(ctx *assert.ScenarioContext) assert.HttpJson {
+			attempts++
+			return assert.Get(server.URL + "/orders/{{.orderId}}").Vars(ctx.Vars).Do().Status(http.StatusOK)
+		}, assert.Retries(2, 0)).
+		Step("update", func(ctx *assert.ScenarioContext) assert.HttpJson {
+			t.Error("dependent step should be skipped")
+			return assert.HttpJson{}
+		}).
+		Step("independent", func(ctx *assert.ScenarioContext) assert.HttpJson {
+			panic("boom")
+		}, assert.DependsOn("create")).
+		Cleanup("second", func(ctx *assert.ScenarioContext) assert.HttpJson {
+			cleaned = append(cleaned, "second")
+			return assert.Post(server.URL + "/orders").Do().IsSuccess()
+		})
+
+	if attempts != 3 {
+		t.Error("attempts", attempts)
+	}
+
+	if s.Check() {
+		t.Error("scenario should fail")
+	}
+
+	ft.finish()
+
+	if strings.Join(cleaned, ",") != "second,first" {
+		t.Error("cleanups", cleaned)
+	}
+
+	var statuses []string
+	for _, r := range s.Results() {
+		statuses = append(statuses, r.Name+"="+r.Status)
+	}
+	if strings.Join(statuses, " ") != "create=passed flaky=failed update=skipped independent=failed second=passed first=passed" {
+		t.Error(statuses)
+	}
+
+	if len(ft.errors) != 1 {
+		t.Fatal(ft.errors)
+	}
+
+	report := ft.errors[0]
+	for _, expected := range []string{
+		"PASSED create\n  request: POST " + server.URL + "/orders\n  response: 201 Created\n    body: {\"data\"",
+		"FAILED flaky (3 attempts)\n  request: GET " + server.URL + "/orders/42\n  response: 404 Not Found",
+		"status: expected 200, got 404 Not Found",
+		"SKIPPED update\n  depends on flaky",
+		"FAILED independent\n  step panicked: boom",
+		"PASSED second (cleanup)",
+	} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("report should contain %q:\n%s", expected, report)
+		}
+	}
+}
+
+func TestScenarioUnknownDependency(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("unknown dependency should panic")
+		}
+	}()
+
+	assert.Scenario(&fakeT{}).Step("fetch", func(ctx *assert.ScenarioContext) assert.HttpJson {
+		return assert.HttpJson{AssertCorrect: true}
+	}, assert.DependsOn("create"))
+}
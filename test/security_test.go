@@ -0,0 +1,74 @@
+package test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	assert "github.com/ohmymajo/http-assert"
+)
+
+func TestAssertSecurityHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Add("Strict-Transport-Security", "max-age=63072000; includeSubDomains; preload")
+	header.Add("Content-Security-Policy", "default-src 'self'; script-src 'self' https://cdn.test; frame-ancestors 'none'")
+	header.Add("X-Content-Type-Options", "nosniff")
+	header.Add("Referrer-Policy", "strict-origin-when-cross-origin")
+	header.Add("Permissions-Policy", "geolocation=()")
+	header.Add("Cross-Origin-Opener-Policy", "same-origin")
+	header.Add("Cross-Origin-Embedder-Policy", "require-corp")
+	header.Add("Cross-Origin-Resource-Policy", "same-site")
+
+	resp := http.Response{
+		Header: header,
+	}
+
+	http := assert.New(&resp)
+	val := http.AssertSecurityHeaders(assert.OWASPSecurityProfile)
+
+	if !val.Check() {
+		t.Error(val.Report())
+	}
+}
+
+func TestAssertSecurityHeadersFail(t *testing.T) {
+	header := http.Header{}
+	header.Add("Strict-Transport-Security", "max-age=300")
+	header.Add("Content-Security-Policy", "default-src 'self'; script-src 'self' 'unsafe-inline'")
+	header.Add("X-Content-Type-Options", "nosniff")
+	header.Add("Server", "nginx/1.25.1")
+
+	resp := http.Response{
+		Header: header,
+	}
+
+	http := assert.New(&resp)
+	val := http.AssertSecurityHeaders(assert.SecurityProfile{
+		HSTSMinMaxAge:         assert.OWASPSecurityProfile.HSTSMinMaxAge,
+		HSTSIncludeSubDomains: true,
+		CSPForbidden:          map[string][]string{"script-src": {"'unsafe-inline'"}},
+		ContentTypeOptions:    true,
+		ForbiddenHeaders:      []string{"Server"},
+	})
+
+	if val.Check() {
+		t.Fail()
+	}
+
+	report := val.Report()
+	expected := []string{
+		"Strict-Transport-Security max-age 5m0s is below",
+		"Strict-Transport-Security is missing includeSubDomains",
+		"Content-Security-Policy script-src allows 'unsafe-inline'",
+		`Server header leaks "nginx/1.25.1"`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(report, e) {
+			t.Errorf("report is missing %q:\n%s", e, report)
+		}
+	}
+
+	if len(val.Failures) != len(expected) {
+		t.Errorf("unexpected failures:\n%s", report)
+	}
+}
@@ -0,0 +1,183 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	assert "github.com/ohmymajo/http-assert"
+)
+
+func TestAssertSoft(t *testing.T) {
+	b := []byte(`{"int": 1, "str": "Hello", "items": [{"id": 1}, {}, {}]}`)
+
+	resp := http.Response{
+		StatusCode: 500,
+		Body:       io.NopCloser(bytes.NewReader(b)),
+	}
+
+	http := assert.New(&resp)
+	val := http.IsSuccess().
+		Soft().
+		AssertBody().
+		Where("int", 2).
+		Has("str").
+		WhereType("str", "int").
+		Each("items", func(h assert.HttpJson) assert.HttpJson {
+			return h.Has("id")
+		})
+
+	if val.Check() {
+		t.Fail()
+	}
+
+	if len(val.Failures) != 4 {
+		t.Error(val.Report())
+	}
+
+	expected := `body: Each("items") item 1: body: Has("id"), item 2: body: Has("id")`
+	if val.Failures[len(val.Failures)-1] != expected {
+		t.Error(val.Report())
+	}
+}
+
+func TestAssertSoftPass(t *testing.T) {
+	b := []byte(`{"int": 1}`)
+
+	resp := http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader(b)),
+	}
+
+	val := assert.That(&resp).
+		Soft().
+		Status(200).
+		Body("int").Equals(1)
+
+	if !val.Verify() || val.Report() != "" {
+		t.Error(val.Report())
+	}
+}
+
+func TestAssertSoftThat(t *testing.T) {
+	b := []byte(`{"id": "7"}`)
+
+	resp := http.Response{
+		StatusCode: 404,
+		Body:       io.NopCloser(bytes.NewReader(b)),
+	}
+
+	val := assert.That(&resp).
+		Soft().
+		Status(200).
+		Header("X-Id").Exists().
+		Body("id").IsType("int")
+
+	if val.Verify() || len(val.Json.Failures) != 3 {
+		t.Error(val.Report())
+	}
+}
+
+func TestAssertSoftMissingPaths(t *testing.T) {
+	b := []byte(`{"obj": {"int": 1}}`)
+
+	resp := http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader(b)),
+	}
+
+	http := assert.New(&resp)
+	val := http.AssertBody().
+		Soft().
+		Has("obj.str").
+		Where("obj.str", "x").
+		WhereNot("obj.str", "x").
+		WhereGt("obj.str", 1).
+		WhereGte("obj.str", 1).
+		WhereLt("obj.str", 1).
+		WhereLte("obj.str", 1).
+		WhereType("obj.str", "string").
+		Where("obj.int", "1").
+		Where("obj", true)
+
+	if val.Check() || len(val.Failures) != 10 {
+		t.Error(val.Report())
+	}
+}
+
+func TestThatSoftMissingPaths(t *testing.T) {
+	resp := http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader([]byte(`{"user": {}}`))),
+	}
+
+	val := assert.That(&resp).
+		Soft().
+		Body("user.name").
+		Exists().
+		Equals("x").
+		NotEquals("x").
+		IsType("string").
+		Gt(1)
+
+	if val.Verify() || len(val.Json.Failures) != 5 {
+		t.Error(val.Report())
+	}
+}
+
+func TestAssertSoftNonObjectBody(t *testing.T) {
+	b := []byte(`[{"id": 1}]`)
+
+	resp := http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader(b)),
+	}
+
+	val := assert.New(&resp).
+		AssertBody().
+		Soft().
+		Where("0.id", 1).
+		Has("id").
+		HasLength("*", 1).
+		WhereAggregate("count", "*.id", "eq", 1)
+
+	expected := []string{
+		`body: Where("0.id", 1): body should be JSON object`,
+		`body: Has("id"): body should be JSON object`,
+	}
+
+	if val.Check() || strings.Join(val.Failures, "\n") != strings.Join(expected, "\n") {
+		t.Error(val.Report())
+	}
+}
+
+func TestAssertSoftUndecodableBody(t *testing.T) {
+	header := http.Header{}
+	header.Add("Content-Type", "text/html")
+
+	resp := http.Response{
+		StatusCode: 500,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader("<h1>oops</h1>")),
+	}
+
+	val := assert.That(&resp).
+		Soft().
+		Status(200).
+		Body("id").Exists().Gt(1).
+		Body("items").HasLength(2)
+
+	expected := []string{
+		`body: cannot decode text/html body as json data`,
+		`body: Has("id"): cannot read the response body`,
+		`body: Compare("id", "gt", 1): cannot read the response body`,
+		`body: HasLength("items", 2): cannot read the response body`,
+	}
+
+	failures := val.Json.Failures
+	if val.Verify() || len(failures) != 5 || !strings.HasPrefix(failures[0], "status: expected 200") || strings.Join(failures[1:], "\n") != strings.Join(expected, "\n") {
+		t.Error(val.Report())
+	}
+}
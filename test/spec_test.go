@@ -0,0 +1,171 @@
+package test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	assert "github.com/ohmymajo/http-assert"
+)
+
+const specYAML = `
+baseURL: {{URL}}
+headers:
+  X-Api-Key: secret
+cases:
+  - name: get user
+    request:
+      method: GET
+      path: /users/7
+      query:
+        expand: "true"
+    expect:
+      status: 200
+      headers:
+        - {path: Content-Type, op: exists}
+        - {path: X-Count, op: gt, value: 3}
+        - {path: X-User, value: 7}
+        - {path: X-User, op: ne, value: 8}
+      body:
+        - {path: id, op: eq, value: 7}
+        - {path: name, op: type, value: string}
+        - {path: tags, op: length, value: 2}
+        - {path: score, op: gte, value: 9.5}
+        - {path: score, op: lt, value: 10}
+        - {path: meta.active, value: true}
+  - request:
+      method: POST
+      path: /users
+      json:
+        name: ann
+    expect:
+      status: 201
+`
+
+const specJSON = `{
+	"baseURL": "{{URL}}",
+	"headers": {"X-Api-Key": "secret"},
+	"cases": [
+		{"name": "failing", "request": {"path": "/users/7"},
+		 "expect": {"status": 404, "body": [
+			{"path": "id", "op": "eq", "value": 8},
+			{"path": "tags", "op": "length", "value": 3},
+			{"path": "missing", "op": "exists"},
+			{"path": "id", "op": "gt", "value": 7},
+			{"path": "id", "op": "lte", "value": 6}
+		 ]}}
+	]
+}`
+
+func specServer() *assert.StubServer {
+	server := assert.NewStubServer()
+	server.On("GET", "/users/{id}").
+		WithHeader("X-Api-Key", "secret").
+		ReplyHeader("X-Count", "5").
+		ReplyHeader("X-User", "{{.Params.id}}").
+		ReplyJSON(map[string]interface{}{
+			"id":    7,
+			"name":  "ann",
+			"tags":  []string{"a", "b"},
+			"score": 9.5,
+			"meta":  map[string]interface{}{"active": true},
+		})
+	server.On("POST", "/users").
+		WithBody(func(h assert.HttpJson) assert.HttpJson { return h.Where("name", "ann") }).
+		Reply(http.StatusCreated)
+
+	return server
+}
+
+func writeSpec(t *testing.T, name, content, url string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(strings.ReplaceAll(content, "{{URL}}", url)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestRunSpecFile(t *testing.T) {
+	server := specServer()
+	defer server.Close()
+
+	assert.RunSpecFile(t, writeSpec(t, "api.yaml", specYAML, server.URL))
+}
+
+func TestSpecRunFailures(t *testing.T) {
+	server := specServer()
+	defer server.Close()
+
+	spec, err := assert.LoadSpec(writeSpec(t, "api.json", specJSON, server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := spec.Run(spec.Cases[0], http.DefaultClient)
+	if r.Passed || r.Name != "failing" {
+		t.Fatal(r)
+	}
+
+	for _, expected := range []string{"status: expected 404", `Where("id", 8)`, `HasLength("tags", 3)`, `Has("missing")`, `WhereGt("id", 7)`, `WhereLte("id", 6)`} {
+		if !strings.Contains(r.Report, expected) {
+			t.Errorf("report should contain %q:\n%s", expected, r.Report)
+		}
+	}
+
+	delete(spec.Headers, "X-Api-Key")
+	r = spec.Run(spec.Cases[0], http.DefaultClient)
+	if r.Passed || !strings.Contains(r.Report, "body: cannot decode") {
+		t.Error(r.Report)
+	}
+
+	spec.BaseURL = "http://127.0.0.1:1"
+	if r := spec.Run(spec.Cases[0], http.DefaultClient); r.Passed || !strings.Contains(r.Report, "cannot send request") {
+		t.Error(r)
+	}
+}
+
+func TestLoadSpecInvalid(t *testing.T) {
+	if _, err := assert.LoadSpec(writeSpec(t, "bad.yaml", "cases:\n  - expect:\n      body:\n        - {path: id, op: matches}\n", "")); err == nil {
+		t.Error("unknown op")
+	}
+
+	if _, err := assert.LoadSpec(writeSpec(t, "bad.json", "{", "")); err == nil {
+		t.Error("invalid json")
+	}
+}
+
+func TestSpecUseEnvironment(t *testing.T) {
+	server := specServer()
+	defer server.Close()
+
+	content := `
+baseURL: http://127.0.0.1:1
+environments:
+  local:
+    baseURL: {{URL}}
+    headers:
+      X-Api-Key: secret
+cases:
+  - request: {path: /users/7}
+    expect: {status: 200}
+`
+	spec, err := assert.LoadSpec(writeSpec(t, "env.yaml", content, server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := spec.Use("staging"); err == nil {
+		t.Error("unknown environment")
+	}
+
+	if err := spec.Use("local"); err != nil {
+		t.Fatal(err)
+	}
+
+	if r := spec.Run(spec.Cases[0], http.DefaultClient); !r.Passed || r.Name != "/users/7" {
+		t.Error(r)
+	}
+}
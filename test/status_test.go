@@ -0,0 +1,105 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	assert "github.com/ohmymajo/http-assert"
+)
+
+func TestAssertStatusClasses(t *testing.T) {
+	resp := http.Response{
+		StatusCode: 201,
+	}
+
+	http := assert.New(&resp)
+	if !http.IsSuccess().StatusIn(200, 201, 204).StatusBetween(200, 201).Status(201).Check() {
+		t.Fail()
+	}
+
+	if http.IsRedirect().Check() || http.IsClientError().Check() || http.IsServerError().Check() || http.IsInformational().Check() {
+		t.Fail()
+	}
+}
+
+func TestAssertStatusChain(t *testing.T) {
+	b := []byte(`{"data": {"id": 1}}`)
+
+	header := http.Header{}
+	header.Add("X-Id", "abc")
+
+	resp := http.Response{
+		StatusCode: 200,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader(b)),
+	}
+
+	http := assert.New(&resp)
+	val := http.IsSuccess().
+		Has("X-Id").
+		AssertBody().
+		WhereType("data.id", "int").
+		AssertHeader().
+		Where("X-Id", "abc").
+		Check()
+
+	if !val {
+		t.Fail()
+	}
+}
+
+func TestAssertStatusReport(t *testing.T) {
+	b := []byte(`{"error": "order not found"}`)
+
+	header := http.Header{}
+	header.Add("Content-Type", "application/json")
+	header.Add("X-Request-Id", "req-1")
+
+	resp := http.Response{
+		StatusCode: 404,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader(b)),
+	}
+
+	http := assert.New(&resp)
+	val := http.IsSuccess()
+
+	if val.Check() {
+		t.Fail()
+	}
+
+	report := val.Report()
+	expected := []string{
+		"expected 2xx, got 404 Not Found",
+		"X-Request-Id: req-1",
+		`body: {"error": "order not found"}`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(report, e) {
+			t.Errorf("report is missing %q:\n%s", e, report)
+		}
+	}
+
+	if !http.AssertBody().Has("error").Check() {
+		t.Error("body should still be readable after the report")
+	}
+}
+
+func TestAssertStatusWithoutResponse(t *testing.T) {
+	server := assert.NewStubServer()
+	defer server.Close()
+
+	assert.Get(server.URL + "/orders").Do()
+
+	val := server.Request(0).AssertHeader().Soft().IsSuccess().StatusIn(200, 201).Status(200)
+	if val.Check() || len(val.Failures) != 3 {
+		t.Fatal(val.Report())
+	}
+
+	if val.Failures[0] != "status: expected 2xx, got no response" || val.Failures[1] != "status: expected 200 or 201, got no response" {
+		t.Error(val.Report())
+	}
+}
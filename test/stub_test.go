@@ -0,0 +1,102 @@
+package test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	assert "github.com/ohmymajo/http-assert"
+)
+
+func TestStubServerRecords(t *testing.T) {
+	server := assert.NewStubServer()
+	defer server.Close()
+
+	assert.Post(server.URL+"/orders").
+		Query("dryRun", "true").
+		Query("page", "2").
+		Header("X-Api-Key", "secret").
+		JSON(map[string]interface{}{"id": 1, "items": []string{"a", "b"}}).
+		Do()
+	assert.Get(server.URL + "/orders/1").Do()
+	assert.Get(server.URL + "/orders/1").Do()
+
+	if len(server.Requests()) != 3 {
+		t.Fatal(server.Requests())
+	}
+
+	r := server.Request(0)
+	if !r.AssertHeader().Has("x-api-key").Where("X-Api-Key", "secret").Check() {
+		t.Error("header")
+	}
+
+	if !r.AssertQuery().Has("dryRun").Where("dryRun", "true").WhereType("dryRun", "string").Check() {
+		t.Error("query")
+	}
+
+	if r.AssertQuery().Has("dryrun").Check() {
+		t.Error("query keys should be case sensitive")
+	}
+
+	if !r.AssertQuery().HasLength("dryRun", 4).HasLengthBetween("page", 1, 2).NotEmpty("page").WhereGte("page", 2).WhereLt("page", 3).Compare("page", "eq", 2).Check() {
+		t.Error("query length and numeric checks")
+	}
+
+	if r := r.AssertQuery().Soft().WhereGt("dryRun", 1).WhereGt("missing", 1); len(r.Failures) != 2 ||
+		r.Failures[0] != `query: WhereGt("dryRun", 1): cannot parse query dryRun value "true" as number` {
+		t.Error(r.Report())
+	}
+
+	if !r.AssertBody().Where("id", 1).HasLength("items", 2).Check() {
+		t.Error("body")
+	}
+
+	if !server.AssertCallCount("GET", "/orders/1", 2).Check() || !server.AssertCalled("POST", "/orders").Check() {
+		t.Error("call count")
+	}
+
+	if r := server.AssertCallCount("GET", "/orders/1", 1); r.Check() || r.Report() != `stub: AssertCallCount("GET", "/orders/1", 1): got 2` {
+		t.Error(r.Report())
+	}
+
+	if r := server.AssertCalled("DELETE", "/orders/1"); r.Check() || r.Report() != `stub: AssertCalled("DELETE", "/orders/1")` {
+		t.Error(r.Report())
+	}
+
+	if !server.AssertCallOrder("POST /orders", "GET /orders/1").Check() {
+		t.Error("call order")
+	}
+
+	expected := `stub: AssertCallOrder(["GET /orders/1" "POST /orders"]): got ["POST /orders" "GET /orders/1" "GET /orders/1"]`
+	if r := server.AssertCallOrder("GET /orders/1", "POST /orders"); r.Check() || r.Report() != expected {
+		t.Error(r.Report())
+	}
+
+	server.Reset()
+	if len(server.Requests()) != 0 {
+		t.Fail()
+	}
+}
+
+func TestStubServerMissingRequest(t *testing.T) {
+	server := assert.NewStubServer()
+	defer server.Close()
+
+	defer func() {
+		r := recover()
+		if r == nil || !strings.Contains(r.(string), "did not receive request 0") {
+			t.Fail()
+		}
+	}()
+
+	server.Request(0)
+}
+
+func TestStubServerDefaultResponse(t *testing.T) {
+	server := assert.NewStubServer()
+	defer server.Close()
+
+	if !assert.Get(server.URL).Do().AssertStatus(http.StatusOK) {
+		t.Fail()
+	}
+}
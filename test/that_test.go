@@ -0,0 +1,104 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	assert "github.com/ohmymajo/http-assert"
+)
+
+func TestThat(t *testing.T) {
+	b := []byte(`{"data": {"id": 7, "name": "order", "items": [1, 2]}}`)
+
+	header := http.Header{}
+	header.Add("X-Id", "abc")
+	header.Add("Content-Length", "42")
+
+	resp := http.Response{
+		StatusCode: 200,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader(b)),
+	}
+
+	val := assert.That(&resp).
+		Status(200).
+		Header("X-Id").Exists().Equals("abc").
+		Header("Content-Length").Lte(1024).
+		Body("data.id").IsType("int").Gt(5).Lt(10).
+		Body("data.name").NotEquals("invoice").
+		Body("data.items").HasLength(2).
+		Expect(`body.data.items.all(i, i > 0)`)
+
+	if !val.Verify() {
+		t.Error(val.Report())
+	}
+}
+
+func TestThatFail(t *testing.T) {
+	b := []byte(`{"data": {"id": "7"}}`)
+
+	resp := http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader(b)),
+	}
+
+	val := assert.That(&resp).
+		Status(200).
+		Body("data.id").IsType("int")
+
+	if val.Verify() || !strings.Contains(val.Report(), `body: WhereType("data.id", "int")`) {
+		t.Error(val.Report())
+	}
+}
+
+func TestThatUndecodableBody(t *testing.T) {
+	header := http.Header{}
+	header.Add("Content-Type", "text/html")
+
+	resp := http.Response{
+		StatusCode: 500,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader("<h1>oops</h1>")),
+	}
+
+	val := assert.That(&resp).
+		Status(200).
+		Body("id").Exists()
+
+	if val.Verify() || !strings.HasPrefix(val.Report(), "status: expected 200, got 500 Internal Server Error") {
+		t.Error(val.Report())
+	}
+
+	resp = http.Response{
+		StatusCode: 204,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("")),
+	}
+
+	val = assert.That(&resp).
+		Status(204).
+		Body("id").Exists()
+
+	if val.Verify() || val.Report() != "body: cannot decode json data" {
+		t.Error(val.Report())
+	}
+
+	header = http.Header{}
+	header.Add("Content-Type", "text/html")
+
+	resp = http.Response{
+		StatusCode: 200,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader("<h1>ok</h1>")),
+	}
+
+	val = assert.That(&resp).
+		Expect(`body.id == 1`)
+
+	if val.Verify() || val.Report() != "body: cannot decode text/html body as json data" {
+		t.Error(val.Report())
+	}
+}
@@ -0,0 +1,99 @@
+package test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	assert "github.com/ohmymajo/http-assert"
+)
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+
+		w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	transport := assert.NewTransport(nil,
+		func(h assert.Http) assert.HttpJson {
+			return h.IsSuccess()
+		},
+		func(h assert.Http) assert.HttpJson {
+			return h.AssertBody().WhereType("id", "int")
+		},
+	)
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(server.URL + "/orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != `{"id": 1}` {
+		t.Errorf("body should be left intact, got %q", body)
+	}
+
+	if !transport.Check() {
+		t.Error(transport.Report())
+	}
+
+	if _, err := client.Get(server.URL + "/missing"); err != nil {
+		t.Fatal(err)
+	}
+
+	violations := transport.Violations()
+	if len(violations) != 1 || violations[0].Request.URL.Path != "/missing" {
+		t.Fatal(transport.Report())
+	}
+
+	if !strings.Contains(transport.Report(), "request: GET "+server.URL+"/missing") {
+		t.Error(transport.Report())
+	}
+
+	transport.Reset()
+	if !transport.Check() {
+		t.Fail()
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+type brokenBody struct{}
+
+func (brokenBody) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func (brokenBody) Close() error {
+	return nil
+}
+
+func brokenTransport() http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: brokenBody{}, Request: req}, nil
+	})
+}
+
+func TestTransportBodyError(t *testing.T) {
+	transport := assert.NewTransport(brokenTransport(), func(h assert.Http) assert.HttpJson {
+		return h.IsSuccess()
+	})
+
+	client := &http.Client{Transport: transport}
+	if _, err := client.Get("http://api.test/orders"); err == nil || !strings.Contains(err.Error(), "connection reset") {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,172 @@
+package assert
+
+import (
+	"net/http"
+
+	"github.com/ohmymajo/http-assert/pkg/validation"
+)
+
+type Assertion struct {
+	Json   HttpJson
+	Target string
+}
+
+func That(resp *http.Response) Assertion {
+	return Assertion{
+		Json: New(resp).AssertHeader(),
+	}
+}
+
+func (a Assertion) Soft() Assertion {
+	a.Json = a.Json.Soft()
+	return a
+}
+
+func (a Assertion) Status(statusCode int) Assertion {
+	a.Json = a.Json.Status(statusCode)
+	return a
+}
+
+func (a Assertion) StatusIn(statusCodes ...int) Assertion {
+	a.Json = a.Json.StatusIn(statusCodes...)
+	return a
+}
+
+func (a Assertion) StatusBetween(min, max int) Assertion {
+	a.Json = a.Json.StatusBetween(min, max)
+	return a
+}
+
+func (a Assertion) IsSuccess() Assertion {
+	a.Json = a.Json.IsSuccess()
+	return a
+}
+
+func (a Assertion) IsRedirect() Assertion {
+	a.Json = a.Json.IsRedirect()
+	return a
+}
+
+func (a Assertion) IsClientError() Assertion {
+	a.Json = a.Json.IsClientError()
+	return a
+}
+
+func (a Assertion) IsServerError() Assertion {
+	a.Json = a.Json.IsServerError()
+	return a
+}
+
+func (a Assertion) Header(name string) Assertion {
+	a.Json = a.Json.AssertHeader()
+	a.Target = name
+	return a
+}
+
+func (a Assertion) Cookie(name string) Assertion {
+	a.Json = a.Json.AssertCookies()
+	a.Target = name
+	return a
+}
+
+func (a Assertion) Body(path string) Assertion {
+	if a.Json.Type != "body" {
+		a.Json = a.Json.AssertBody()
+	}
+
+	a.Target = path
+	return a
+}
+
+func (a Assertion) Exists() Assertion {
+	a.Json = a.Json.Has(a.Target)
+	return a
+}
+
+func (a Assertion) Equals(value interface{}) Assertion {
+	a.Json = a.Json.Where(a.Target, value)
+	return a
+}
+
+func (a Assertion) NotEquals(value interface{}) Assertion {
+	a.Json = a.Json.WhereNot(a.Target, value)
+	return a
+}
+
+func (a Assertion) IsType(valueType string) Assertion {
+	a.Json = a.Json.WhereType(a.Target, valueType)
+	return a
+}
+
+func (a Assertion) Gt(value interface{}) Assertion {
+	a.Json = a.Json.Compare(a.Target, "gt", value)
+	return a
+}
+
+func (a Assertion) Gte(value interface{}) Assertion {
+	a.Json = a.Json.Compare(a.Target, "gte", value)
+	return a
+}
+
+func (a Assertion) Lt(value interface{}) Assertion {
+	a.Json = a.Json.Compare(a.Target, "lt", value)
+	return a
+}
+
+func (a Assertion) Lte(value interface{}) Assertion {
+	a.Json = a.Json.Compare(a.Target, "lte", value)
+	return a
+}
+
+func (a Assertion) HasLength(length int) Assertion {
+	a.Json = a.Json.HasLength(a.Target, length)
+	return a
+}
+
+func (a Assertion) IsEmpty() Assertion {
+	a.Json = a.Json.IsEmpty(a.Target)
+	return a
+}
+
+func (a Assertion) NotEmpty() Assertion {
+	a.Json = a.Json.NotEmpty(a.Target)
+	return a
+}
+
+func (a Assertion) Expect(expression string) Assertion {
+	if a.Json.Type != "body" {
+		a.Json = a.Json.AssertBody()
+	}
+
+	a.Json = a.Json.Expect(expression)
+	return a
+}
+
+func (a Assertion) Verify() bool {
+	return a.Json.Check()
+}
+
+func (a Assertion) Report() string {
+	return a.Json.Report()
+}
+
+func (h HttpJson) Compare(fieldName, op string, value interface{}) HttpJson {
+	var correct bool
+	if (h.Type == "header" || h.Type == "query") && h.evaluate() {
+		var err error
+		if correct, err = h.compareText(fieldName, op, value); err != nil {
+			return h.result(false, "Compare(%q, %q, %#v): %s", fieldName, op, value, err)
+		}
+	} else if h.Type == "body" && h.evaluate() {
+		if err := h.bodyError(false); err != nil {
+			return h.bodyFailure(err, "Compare(%q, %q, %#v)", fieldName, op, value)
+		}
+
+		v := findValue(fieldName, h.Body)
+		if v != nil {
+			correct = validation.CompareValues(v, value, op)
+		}
+	}
+
+	return h.result(correct, "Compare(%q, %q, %#v)", fieldName, op, value)
+}
@@ -0,0 +1,106 @@
+package assert
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+type Transport struct {
+	Base   http.RoundTripper
+	Checks []func(Http) HttpJson
+
+	mu         sync.Mutex
+	violations []Violation
+}
+
+type Violation struct {
+	Request *http.Request
+	Report  string
+}
+
+func NewTransport(base http.RoundTripper, checks ...func(Http) HttpJson) *Transport {
+	return &Transport{
+		Base:   base,
+		Checks: checks,
+	}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Request == nil {
+		resp.Request = req
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, check := range t.Checks {
+		r := *resp
+		r.Header = resp.Header.Clone()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		if report, ok := runCheck(check, &r); !ok {
+			t.mu.Lock()
+			t.violations = append(t.violations, Violation{Request: req, Report: report})
+			t.mu.Unlock()
+		}
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+func (t *Transport) Violations() []Violation {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]Violation(nil), t.violations...)
+}
+
+func (t *Transport) Check() bool {
+	return len(t.Violations()) == 0
+}
+
+func (t *Transport) Report() string {
+	var reports []string
+	for _, v := range t.Violations() {
+		reports = append(reports, v.Report)
+	}
+
+	return strings.Join(reports, "\n\n")
+}
+
+func (t *Transport) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.violations = nil
+}
+
+func runCheck(check func(Http) HttpJson, resp *http.Response) (report string, ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			report = fmt.Sprintf("check panicked: %v\n%s", r, requestSummary(resp.Request))
+			ok = false
+		}
+	}()
+
+	result := check(New(resp))
+	return result.Report(), result.Check()
+}